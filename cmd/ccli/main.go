@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 
 	"github.com/urfave/cli/v3"
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
 	"gitlab.com/locke-codes/container-cli/internal/install"
 )
 
@@ -15,6 +19,20 @@ var version string
 
 // main is the entry point of the application. It sets up the CLI interface with app configuration, commands, and flags.
 func main() {
+	globals.Version = version
+	// Invoked through the link of a project command: run the command in its container and pass on its exit code
+	if alias := install.CommandAlias(os.Args[0]); alias != "" {
+		log.SetOutput(io.Discard)
+		code, err := install.RunCommand(alias, os.Args[1:])
+		if !errors.Is(err, install.ErrUnknownCommand) {
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "ccli: %v\n", err)
+			}
+			os.Exit(code)
+		}
+		// Not a project command, e.g. a renamed ccli binary such as ccli-dev: run the command line of ccli
+		log.SetOutput(os.Stderr)
+	}
 	cmd := &cli.Command{
 		Name:  "Container CLI",
 		Usage: "Execute applications in containers",
@@ -48,6 +66,38 @@ func main() {
 					return nil
 				},
 			},
+			{
+				Name:      "engine",
+				Usage:     "Show the container engine, or change it and relink the commands of all projects",
+				UsageText: "ccli engine [docker|podman|nerdctl|docker-api|podman-api]",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					engine := cmd.Args().First()
+					if engine == "" {
+						current, err := config.GetContainerEngineFromConfig()
+						if err != nil {
+							return err
+						}
+						fmt.Println(current)
+						return nil
+					}
+					return install.SetEngine(engine)
+				},
+			},
+			{
+				Name:      "prune",
+				Usage:     "Remove stopped containers left behind by project commands",
+				UsageText: "ccli prune [--dry-run]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "If set, the containers are only listed, not removed.",
+						Value: false,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return install.Prune(cmd.Bool("dry-run"))
+				},
+			},
 			{
 				Name:  "version",
 				Usage: "Get the version of the CLI",
@@ -72,7 +122,19 @@ func main() {
 							},
 							&cli.StringFlag{
 								Name:  "url",
-								Usage: "Git url for the repository. E.g. ssh://git@gitlab.com/locke-codes/container-cli.git",
+								Usage: "Git url for the repository, or an archive, s3:: or gcs:: source. E.g. ssh://git@gitlab.com/locke-codes/container-cli.git",
+							},
+							&cli.StringFlag{
+								Name:  "ref",
+								Usage: "Git branch, tag or commit to install. Defaults to the default branch of the repository",
+							},
+							&cli.StringFlag{
+								Name:  "path",
+								Usage: "Local directory to build the project from in place, instead of cloning a url. E.g. ./my-tool",
+							},
+							&cli.StringFlag{
+								Name:  "image",
+								Usage: "Prebuilt image to pull instead of building the project. E.g. registry.example.com/tool:1.0.0 or tool@sha256:...",
 							},
 							&cli.StringFlag{
 								Name:  "dest",
@@ -80,28 +142,152 @@ func main() {
 							},
 							&cli.StringFlag{
 								Name:  "command",
-								Usage: "Default command to execute inside the container. E.g. 'bash'",
+								Usage: "Default command to execute inside the container. E.g. 'bash'. Overrides the project manifest",
 							},
 							&cli.StringFlag{
 								Name:  "alias",
-								Usage: "Local command alias. E.g. 'bs' for 'big-salad' or 'hello' for 'hello-world",
+								Usage: "Local command alias. E.g. 'bs' for 'big-salad' or 'hello' for 'hello-world'. Overrides the project manifest",
+							},
+							&cli.StringSliceFlag{
+								Name:  "cmd",
+								Usage: "Additional command of the image in ALIAS=COMMAND form, installed as its own local command. Can be repeated",
+							},
+							&cli.StringSliceFlag{
+								Name:  "build-arg",
+								Usage: "Build argument in KEY=VALUE form. ${VAR} is expanded from the environment. Can be repeated",
+							},
+							&cli.StringFlag{
+								Name:  "target",
+								Usage: "Stage of a multi-stage Dockerfile to build",
+							},
+							&cli.StringSliceFlag{
+								Name:  "label",
+								Usage: "Image label in KEY=VALUE form. Can be repeated",
+							},
+							&cli.StringFlag{
+								Name:  "platform",
+								Usage: "Platform of the image to build. E.g. linux/amd64",
+							},
+							&cli.StringFlag{
+								Name:  "engine",
+								Usage: "Container engine for this project. Defaults to the global container engine",
+							},
+							&cli.BoolFlag{
+								Name:  "quiet",
+								Usage: "If set, the build output is only written to the build log",
+								Value: false,
+							},
+							&cli.BoolFlag{
+								Name:  "locked",
+								Usage: "If set, refuse to install a commit, Dockerfile or prebuilt image that does not match ccli.lock",
+								Value: false,
 							},
 						},
 						Action: func(ctx context.Context, cmd *cli.Command) error {
 							args := map[string]string{
 								"name":    cmd.String("name"),
 								"url":     cmd.String("url"),
+								"ref":     cmd.String("ref"),
+								"path":    cmd.String("path"),
+								"image":   cmd.String("image"),
 								"dest":    cmd.String("dest"),
 								"command": cmd.String("command"),
 								"alias":   cmd.String("alias"),
 							}
-							project := install.NewProject(args)
-							fmt.Printf("Installing project: %s\nFrom url: %s\nTo directory: %s\n", project.Name, project.URL, project.DestinationDirectory)
-							err := project.Install()
+							err := install.CheckEngine(cmd.String("engine"))
+							if err != nil {
+								return err
+							}
+							commands, err := install.ParseCommands(cmd.StringSlice("cmd"))
 							if err != nil {
-								panic(err)
+								return err
+							}
+							project := install.NewProject(args)
+							project.Commands = commands
+							project.BuildArgs = cmd.StringSlice("build-arg")
+							project.Target = cmd.String("target")
+							project.Labels = cmd.StringSlice("label")
+							project.Platform = cmd.String("platform")
+							project.Locked = cmd.Bool("locked")
+							project.Engine = cmd.String("engine")
+							project.Quiet = cmd.Bool("quiet")
+							fmt.Printf("Installing project: %s\nFrom url: %s\nTo directory: %s\n", project.Name, project.URL, project.Path())
+							return project.Install()
+						},
+					},
+					{
+						Name:      "list",
+						Usage:     "List installed projects",
+						UsageText: "ccli project list [--output table|json|yaml]",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "output",
+								Aliases: []string{"o"},
+								Usage:   "Output format. One of table, json or yaml",
+								Value:   "table",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return install.ProjectList(cmd.String("output"))
+						},
+					},
+					{
+						Name:      "update",
+						Usage:     "Fetch the latest changes of a project and rebuild it if the commit changed",
+						UsageText: "ccli project update <name> [--ref <ref>] [--quiet]",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "ref",
+								Usage: "Pin the project to a new git branch, tag or commit. E.g. 'main', 'v1.2.0'",
+							},
+							&cli.BoolFlag{
+								Name:  "quiet",
+								Usage: "If set, the build output is only written to the build log",
+								Value: false,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							name := cmd.Args().First()
+							if name == "" {
+								return fmt.Errorf("project name is required")
+							}
+							return install.ProjectUpdate(name, cmd.String("ref"), cmd.Bool("quiet"))
+						},
+					},
+					{
+						Name:      "rollback",
+						Usage:     "Point a project at the image of an earlier commit without rebuilding",
+						UsageText: "ccli project rollback <name> [commit]",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							name := cmd.Args().First()
+							if name == "" {
+								return fmt.Errorf("project name is required")
+							}
+							return install.ProjectRollback(name, cmd.Args().Get(1))
+						},
+					},
+					{
+						Name:      "uninstall",
+						Usage:     "Uninstall a project",
+						UsageText: "ccli project uninstall <name> [--keep-image] [--keep-source]",
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "keep-image",
+								Usage: "If set, the container image of the project is not removed.",
+								Value: false,
+							},
+							&cli.BoolFlag{
+								Name:  "keep-source",
+								Usage: "If set, the cloned source of the project is not removed.",
+								Value: false,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							name := cmd.Args().First()
+							if name == "" {
+								return fmt.Errorf("project name is required")
 							}
-							return nil
+							return install.ProjectUninstall(name, cmd.Bool("keep-image"), cmd.Bool("keep-source"))
 						},
 					},
 				},
@@ -4,10 +4,15 @@ go 1.23
 
 require (
 	github.com/hashicorp/go-getter v1.7.6
+	github.com/hashicorp/go-version v1.6.0
 	github.com/knadh/koanf v1.5.0
 	github.com/manifoldco/promptui v0.9.0
+	github.com/moby/patternmatcher v0.6.0
 	github.com/urfave/cli/v3 v3.0.0-beta1
 	gitlab.com/locke-codes/go-binary-updater v0.1.5
+	golang.org/x/sys v0.27.0
+	golang.org/x/term v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -28,7 +33,6 @@ require (
 	github.com/googleapis/gax-go/v2 v2.6.0 // indirect
 	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
 	github.com/hashicorp/go-safetemp v1.0.0 // indirect
-	github.com/hashicorp/go-version v1.6.0 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	github.com/klauspost/compress v1.17.11 // indirect
 	github.com/mitchellh/copystructure v1.2.0 // indirect
@@ -42,7 +46,6 @@ require (
 	golang.org/x/net v0.30.0 // indirect
 	golang.org/x/oauth2 v0.23.0 // indirect
 	golang.org/x/sync v0.10.0 // indirect
-	golang.org/x/sys v0.27.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 	golang.org/x/xerrors v0.0.0-20220907171357-04be3eba64a2 // indirect
 	google.golang.org/api v0.100.0 // indirect
@@ -50,7 +53,6 @@ require (
 	google.golang.org/genproto v0.0.0-20221025140454-527a21cfbd71 // indirect
 	google.golang.org/grpc v1.67.0 // indirect
 	google.golang.org/protobuf v1.35.1 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace gitlab.com/locke-codes/container-cli => ./
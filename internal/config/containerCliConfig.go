@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"slices"
 
 	"github.com/knadh/koanf"
 	"github.com/knadh/koanf/parsers/yaml"
@@ -13,9 +14,14 @@ import (
 )
 
 // ContainerCliConfig represents the configuration for the container CLI, including engine, path, and project details.
+// The environment settings are defaults that are merged into the environment of every project.
 type ContainerCliConfig struct {
 	ContainerEngine string          `koanf:"containerEngine"`
+	RootlessEngines []string        `koanf:"rootlessEngines"` // Engines that ran rootless when they were last probed
 	Path            string          `koanf:"path"`
+	PassEnv         []string        `koanf:"passEnv"`
+	Env             []string        `koanf:"env"`
+	EnvFile         string          `koanf:"envFile"`
 	Projects        []ProjectConfig `koanf:"projects"`
 }
 
@@ -59,7 +65,7 @@ func GetContainerEngineFromConfig() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	fmt.Printf("Container engine: %s\n", configFile.ContainerEngine)
+	log.Printf("Container engine: %s", configFile.ContainerEngine)
 	if configFile.ContainerEngine == "" {
 		return "", fmt.Errorf("containerEngine not set")
 	}
@@ -89,6 +95,10 @@ func (c *ContainerCliConfig) LoadConfig() error {
 		return fmt.Errorf("error parsing YAML: %w", err)
 	}
 	c.ContainerEngine = config.ContainerEngine
+	c.RootlessEngines = config.RootlessEngines
+	c.PassEnv = config.PassEnv
+	c.Env = config.Env
+	c.EnvFile = config.EnvFile
 	c.Projects = config.Projects
 	return nil
 }
@@ -114,6 +124,19 @@ func (c *ContainerCliConfig) SaveConfig() error {
 	return nil
 }
 
+// SetRootless records whether the engine with the given name runs rootless and reports whether the record changed.
+func (c *ContainerCliConfig) SetRootless(engine string, rootless bool) bool {
+	if slices.Contains(c.RootlessEngines, engine) == rootless {
+		return false
+	}
+	if rootless {
+		c.RootlessEngines = append(c.RootlessEngines, engine)
+	} else {
+		c.RootlessEngines = slices.DeleteFunc(c.RootlessEngines, func(name string) bool { return name == engine })
+	}
+	return true
+}
+
 // GetProject retrieves the ProjectConfig for the given project name from the ContainerCliConfig.
 // Returns nil if the project is not found.
 func (c *ContainerCliConfig) GetProject(name string) *ProjectConfig {
@@ -138,6 +161,17 @@ func (c *ContainerCliConfig) ReplaceProjectByName(name string, newProject Projec
 	return fmt.Errorf("project with name %s not found", name)
 }
 
+// RemoveProjectByName removes the project with the given name from the slice of projects
+func (c *ContainerCliConfig) RemoveProjectByName(name string) error {
+	for i, project := range c.Projects {
+		if project.Name == name {
+			c.Projects = append(utils.CopySlice(c.Projects[:i]), c.Projects[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("project with name %s not found", name)
+}
+
 // GetProjectPath returns the file system path of the specified project name if found, otherwise it returns an empty string.
 func (c *ContainerCliConfig) GetProjectPath(name string) string {
 	project := c.GetProject(name)
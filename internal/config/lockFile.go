@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/providers/structs"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/utils"
+)
+
+// LockFileName is the name of the lock file written next to the container CLI configuration file.
+const LockFileName = "ccli.lock"
+
+// LockFile records exactly what is installed for each project, so environments can be reproduced across a team.
+type LockFile struct {
+	Path     string        `koanf:"-"`
+	Projects []ProjectLock `koanf:"projects"`
+}
+
+// ProjectLock records the resolved commit, the image and the Dockerfile of an installed project, and the ccli version
+// that installed it.
+type ProjectLock struct {
+	Name           string `koanf:"name"`
+	Commit         string `koanf:"commit"`
+	ImageID        string `koanf:"imageId"`
+	ImageDigest    string `koanf:"imageDigest"`
+	DockerfileHash string `koanf:"dockerfileHash"`
+	CcliVersion    string `koanf:"ccliVersion"`
+}
+
+// DefaultLockFilePath returns the path of the lock file next to the container CLI configuration file.
+func DefaultLockFilePath() string {
+	return filepath.Join(filepath.Dir(globals.DefaultContainerCliConfigPath), LockFileName)
+}
+
+// LoadLockFile reads the lock file at the given path. An empty LockFile is returned if the file does not exist.
+func LoadLockFile(path string) (*LockFile, error) {
+	lockFile := LockFile{Path: path}
+	if !utils.FileExists(path) {
+		return &lockFile, nil
+	}
+	lockKoanf := koanf.New(".")
+	if err := lockKoanf.Load(file.Provider(path), parser); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	if err := lockKoanf.Unmarshal("", &lockFile); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	lockFile.Path = path
+	return &lockFile, nil
+}
+
+// Save writes the lock file in YAML format to its path.
+func (l *LockFile) Save() error {
+	lockKoanf := koanf.New(".")
+	if err := lockKoanf.Load(structs.Provider(l, "koanf"), nil); err != nil {
+		return err
+	}
+	marshalledBytes, err := lockKoanf.Marshal(parser)
+	if err != nil {
+		return err
+	}
+	return utils.WriteToFile(l.Path, marshalledBytes)
+}
+
+// GetProject returns the lock entry of the project with the given name, or nil if the project is not locked.
+func (l *LockFile) GetProject(name string) *ProjectLock {
+	for i := range l.Projects {
+		if l.Projects[i].Name == name {
+			return &l.Projects[i]
+		}
+	}
+	return nil
+}
+
+// SetProject adds the lock entry of a project or replaces the existing entry with the same name.
+func (l *LockFile) SetProject(projectLock ProjectLock) {
+	if existing := l.GetProject(projectLock.Name); existing != nil {
+		*existing = projectLock
+		return
+	}
+	l.Projects = append(l.Projects, projectLock)
+}
+
+// RemoveProject removes the lock entry of the project with the given name if it exists.
+func (l *LockFile) RemoveProject(name string) {
+	for i, project := range l.Projects {
+		if project.Name == name {
+			l.Projects = append(utils.CopySlice(l.Projects[:i]), l.Projects[i+1:]...)
+			return
+		}
+	}
+}
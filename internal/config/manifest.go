@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/file"
+	"gitlab.com/locke-codes/container-cli/internal/utils"
+)
+
+// ManifestFileName is the name of the optional manifest file at the root of a project repository.
+const ManifestFileName = ".ccli.yaml"
+
+// Manifest declares how a project is built and run. Tool authors publish it in their repository so that the project
+// installs correctly with just a URL. Paths are relative to the root of the repository.
+type Manifest struct {
+	Command     string          `koanf:"command"`
+	Alias       string          `koanf:"alias"`
+	Commands    []CommandConfig `koanf:"commands"`
+	Dockerfile  string          `koanf:"dockerfile"`
+	Context     string          `koanf:"context"`
+	Image       string          `koanf:"image"`
+	BuildArgs   []string        `koanf:"buildArgs"`
+	Volumes     []VolumeConfig  `koanf:"volumes"`
+	PassEnv     []string        `koanf:"passEnv"`
+	Env         []string        `koanf:"env"`
+	Workdir     string          `koanf:"workdir"`
+	TTY         string          `koanf:"tty"`
+	UserMapping string          `koanf:"userMapping"`
+	Home        string          `koanf:"home"`
+	HomeFiles   []string        `koanf:"homeFiles"`
+	MinVersion  string          `koanf:"minVersion"`
+}
+
+// LoadManifest reads the manifest from the given project directory. An empty Manifest is returned if the directory
+// is empty or does not contain a manifest file.
+func LoadManifest(directory string) (*Manifest, error) {
+	var manifest Manifest
+	manifestPath := filepath.Join(directory, ManifestFileName)
+	if directory == "" || !utils.FileExists(manifestPath) {
+		return &manifest, nil
+	}
+	manifestKoanf := koanf.New(".")
+	if err := manifestKoanf.Load(file.Provider(manifestPath), parser); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", manifestPath, err)
+	}
+	if err := manifestKoanf.Unmarshal("", &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", manifestPath, err)
+	}
+	// The Dockerfile and the build context must stay inside the project, so a manifest cannot build from elsewhere
+	for _, value := range []string{manifest.Dockerfile, manifest.Context} {
+		if value != "" && !filepath.IsLocal(value) {
+			return nil, fmt.Errorf("invalid path %s in %s: must be a relative path inside the project", value, manifestPath)
+		}
+	}
+	return &manifest, nil
+}
+
+// DockerfilePath returns the path of the Dockerfile inside the project directory. It defaults to "Dockerfile".
+func (m *Manifest) DockerfilePath(directory string) string {
+	if m.Dockerfile == "" {
+		return filepath.Join(directory, "Dockerfile")
+	}
+	return filepath.Join(directory, m.Dockerfile)
+}
+
+// ContextPath returns the path of the build context inside the project directory. It defaults to the directory itself.
+func (m *Manifest) ContextPath(directory string) string {
+	return filepath.Join(directory, m.Context)
+}
+
+// CheckVersion returns an error if the given ccli version is older than the minimum version the manifest requires.
+// Development builds without a valid version are not checked.
+func (m *Manifest) CheckVersion(current string) error {
+	if m.MinVersion == "" {
+		return nil
+	}
+	minVersion, err := version.NewVersion(m.MinVersion)
+	if err != nil {
+		return fmt.Errorf("invalid minVersion %s in %s: %w", m.MinVersion, ManifestFileName, err)
+	}
+	currentVersion, err := version.NewVersion(current)
+	if err != nil {
+		return nil
+	}
+	if currentVersion.LessThan(minVersion) {
+		return fmt.Errorf("project requires ccli %s or newer but this is %s, run 'ccli update' first", minVersion, currentVersion)
+	}
+	return nil
+}
@@ -1,13 +1,202 @@
 package config
 
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gitlab.com/locke-codes/container-cli/internal/utils"
+)
+
+const (
+	// SourceGit marks a project that is cloned from a git repository.
+	SourceGit = "git"
+	// SourceLocal marks a project that is built in place from a local directory.
+	SourceLocal = "local"
+	// SourceHTTP marks a project that is downloaded as an archive over http(s).
+	SourceHTTP = "http"
+	// SourceS3 marks a project that is downloaded from Amazon S3.
+	SourceS3 = "s3"
+	// SourceGCS marks a project that is downloaded from Google Cloud Storage.
+	SourceGCS = "gcs"
+	// SourceFile marks a project that is copied from a file:// directory or archive.
+	SourceFile = "file"
+	// SourceImage marks a project that only uses a prebuilt image and has no source.
+	SourceImage = "image"
+)
+
+// TTY settings of a project. With TTYAuto a TTY is allocated when stdin and stdout are terminals.
+const (
+	TTYAuto   = "auto"
+	TTYAlways = "always"
+	TTYNever  = "never"
+)
+
+// User mappings of a project, which decide how the host user is mapped into the container. With UserMappingAuto the
+// host user is mapped with keep-id on podman and with the host UID and GID on the other engines.
+const (
+	UserMappingAuto   = "auto"
+	UserMappingKeepID = "keep-id"
+	UserMappingUser   = "user"
+	UserMappingNone   = "none"
+)
+
+// Home settings of a project. HomeHost mounts the home directory of the user, HomeIsolated mounts a private home
+// directory of the project instead.
+const (
+	HomeHost     = "host"
+	HomeIsolated = "isolated"
+)
+
+// WorkdirImage is the workdir setting that keeps the WORKDIR of the image instead of starting in the mapped working
+// directory.
+const WorkdirImage = "image"
+
 // ProjectConfig defines the configuration for a specific project within the container CLI system.
 // It includes details such as project name, file paths, and default settings for build and runtime.
 type ProjectConfig struct {
-	Name           string `koanf:"name"`
-	Path           string `koanf:"path"`
-	Dockerfile     string `koanf:"dockerfile"`
-	BuildDirectory string `koanf:"buildDirectory"`
-	BuildContext   string `koanf:"buildContext"`
-	DefaultCommand string `koanf:"defaultCommand"`
-	CommandAlias   string `koanf:"commandAlias"`
+	Name           string          `koanf:"name"`
+	URL            string          `koanf:"url"`
+	Ref            string          `koanf:"ref"`
+	Source         string          `koanf:"source"`
+	Path           string          `koanf:"path"`
+	Commit         string          `koanf:"commit"`
+	Dockerfile     string          `koanf:"dockerfile"`
+	BuildDirectory string          `koanf:"buildDirectory"`
+	BuildContext   string          `koanf:"buildContext"`
+	BuildArgs      []string        `koanf:"buildArgs"`
+	Target         string          `koanf:"target"`
+	Labels         []string        `koanf:"labels"`
+	Platform       string          `koanf:"platform"`
+	Image          string          `koanf:"image"`
+	ImageTag       string          `koanf:"imageTag"`
+	History        []string        `koanf:"history"`
+	DefaultCommand string          `koanf:"defaultCommand"`
+	CommandAlias   string          `koanf:"commandAlias"`
+	Commands       []CommandConfig `koanf:"commands"`
+	Volumes        []VolumeConfig  `koanf:"volumes"`
+	PassEnv        []string        `koanf:"passEnv"` // Names or glob patterns of host variables passed into the container
+	Env            []string        `koanf:"env"`     // Variables in KEY=VALUE form. ${VAR} references are expanded
+	EnvFile        string          `koanf:"envFile"` // File of KEY=VALUE lines passed to the container
+	Workdir        string          `koanf:"workdir"`
+	Engine         string          `koanf:"engine"`         // Container engine of the project. Empty uses the global engine
+	TTY            string          `koanf:"tty"`            // One of auto, always or never. Empty means auto
+	KeepContainers bool            `koanf:"keepContainers"` // Keep the containers of runs instead of removing them
+	UserMapping    string          `koanf:"userMapping"`    // One of auto, keep-id, user or none. Empty means auto
+	Home           string          `koanf:"home"`           // One of host or isolated. Empty means host
+	HomeFiles      []string        `koanf:"homeFiles"`      // Paths of the host home mapped into an isolated home
+}
+
+// CommandConfig defines an additional command of the project image and the local alias it is installed as.
+type CommandConfig struct {
+	Alias   string `koanf:"alias"`
+	Command string `koanf:"command"`
+}
+
+// VolumeConfig defines an additional volume mounted into the project container. Either a host path is bind mounted,
+// or a named volume of the container engine.
+type VolumeConfig struct {
+	Host      string `koanf:"host"` // Host path. ~ and ${VAR} references are expanded
+	Name      string `koanf:"name"` // Name of a named volume, used instead of a host path
+	Container string `koanf:"container"`
+	Mode      string `koanf:"mode"` // Comma separated list of ro, rw, z and Z. E.g. ro,z
+}
+
+// volumeName matches the names the container engines accept for named volumes.
+var volumeName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Mapping validates the volume and returns it in HOST:CONTAINER[:MODE] form, with the host path expanded. Host paths
+// must be absolute after expansion and exist, so a typo does not make the engine create an empty directory.
+func (v VolumeConfig) Mapping() (string, error) {
+	if v.Container == "" || !path.IsAbs(v.Container) {
+		return "", fmt.Errorf("volume %s: container path %q must be absolute", v.source(), v.Container)
+	}
+	if err := validateVolumeMode(v.Mode); err != nil {
+		return "", fmt.Errorf("volume %s: %w", v.source(), err)
+	}
+	var source string
+	switch {
+	case v.Host != "" && v.Name != "":
+		return "", fmt.Errorf("volume for %s: set either a host path or a name, not both", v.Container)
+	case v.Name != "":
+		if !volumeName.MatchString(v.Name) {
+			return "", fmt.Errorf("volume for %s: invalid volume name %q", v.Container, v.Name)
+		}
+		source = v.Name
+	case v.Host != "":
+		hostPath, err := v.HostPath()
+		if err != nil {
+			return "", err
+		}
+		if _, err = os.Stat(hostPath); err != nil {
+			return "", fmt.Errorf("volume %s: host path %s does not exist", v.Host, hostPath)
+		}
+		source = hostPath
+	default:
+		return "", fmt.Errorf("volume for %s: a host path or a name is required", v.Container)
+	}
+	mapping := fmt.Sprintf("%s:%s", source, v.Container)
+	if v.Mode != "" {
+		mapping = fmt.Sprintf("%s:%s", mapping, v.Mode)
+	}
+	return mapping, nil
+}
+
+// HostPath returns the expanded host path of the volume, which must be absolute. Named volumes have none. $VAR and
+// ${VAR} references must name variables that are set, so a missing variable cannot turn ${XDG_CONFIG_HOME}/ into /.
+func (v VolumeConfig) HostPath() (string, error) {
+	if v.Host == "" {
+		return "", nil
+	}
+	var unset []string
+	hostPath := os.Expand(v.Host, func(name string) string {
+		value := os.Getenv(name)
+		if value == "" {
+			unset = append(unset, name)
+		}
+		return value
+	})
+	if len(unset) > 0 {
+		return "", fmt.Errorf("volume %s: environment variable %s is not set", v.Host, strings.Join(unset, ", "))
+	}
+	hostPath, err := utils.ExpandPath(hostPath)
+	if err != nil {
+		return "", fmt.Errorf("volume %s: %w", v.Host, err)
+	}
+	if !filepath.IsAbs(hostPath) {
+		return "", fmt.Errorf("volume %s: host path %s must be absolute or start with ~", v.Host, hostPath)
+	}
+	return hostPath, nil
+}
+
+// source returns the host path or name of the volume for error messages.
+func (v VolumeConfig) source() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return v.Host
+}
+
+// validateVolumeMode checks a comma separated list of volume modes. ro and rw, and z (shared SELinux label) and Z
+// (private SELinux label), exclude each other.
+func validateVolumeMode(mode string) error {
+	if mode == "" {
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, option := range strings.Split(mode, ",") {
+		switch option {
+		case "ro", "rw", "z", "Z":
+		default:
+			return fmt.Errorf("invalid mode %q: must be a comma separated list of ro, rw, z and Z", mode)
+		}
+		seen[option] = true
+	}
+	if seen["ro"] && seen["rw"] || seen["z"] && seen["Z"] {
+		return fmt.Errorf("invalid mode %q: ro and rw, and z and Z, cannot be combined", mode)
+	}
+	return nil
 }
@@ -1,153 +1,590 @@
 package container
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"maps"
 	"os"
-	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/engine"
 	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/utils"
+	"golang.org/x/term"
 )
 
+// ProjectLabel is the label that marks the containers of a project with the project name.
+const ProjectLabel = "ccli.project"
+
 // Container represents configuration and attributes for managing container settings.
 type Container struct {
+	ProjectName               string
 	BuildContext              string
 	BuildDirectory            string
 	ContainerEngine           string
+	Engine                    engine.Engine // Engine backend. Defaults to the command line client named by ContainerEngine
 	ContextDirectoryContainer string
 	ContextDirectoryHost      string
 	Dockerfile                string
 	ImageName                 string
 	ImageTag                  string
+	CommitTag                 string // Additional tag with the short commit SHA the image is built from
+	Prebuilt                  bool   // The image is pulled from a registry instead of built
 	UserHomeContainer         string
 	UserHomeHost              string
+	IsolatedHome              bool     // UserHomeHost is a private home directory of the project
+	HomeFiles                 []string // Paths relative to the home directory of the user mapped into an isolated home
 	DefaultCommand            string
+	BuildArgs                 []string
+	Target                    string
+	Labels                    []string
+	Platform                  string
+	Volumes                   []config.VolumeConfig
+	ManifestVolumes           []config.VolumeConfig
+	PassEnv                   []string // Names or glob patterns of host variables passed into the container
+	Env                       []string // Variables in KEY=VALUE form. ${VAR} references are expanded when run
+	EnvFiles                  []string
+	Workdir                   string // Directory the container starts in. Empty keeps the image's WORKDIR
+	TTY                       string // One of auto, always or never
+	KeepContainers            bool   // Containers are removed when they exit unless set
+	UserMapping               string // One of auto, keep-id, user or none
+	Rootless                  bool   // The engine ran rootless when it was last probed, at install or engine change
 }
 
-// GetRunCommand returns the command used for running the application
-func (p Container) GetRunCommand() []string {
+// shortCommitLength is the number of characters of a commit SHA used for image tags.
+const shortCommitLength = 12
+
+// ShortCommit shortens a commit SHA to the length used for image tags.
+func ShortCommit(commit string) string {
+	if len(commit) > shortCommitLength {
+		return commit[:shortCommitLength]
+	}
+	return commit
+}
+
+// Image returns the image reference the container runs. Prebuilt images are used as given, locally built images are
+// referenced by name and tag.
+func (p Container) Image() string {
+	if p.Prebuilt || p.ImageTag == "" {
+		return p.ImageName
+	}
+	return fmt.Sprintf("%s:%s", p.ImageName, p.ImageTag)
+}
+
+// RunOptions returns the options used for running the application with the container engine.
+func (p Container) RunOptions() engine.RunOptions {
 	// Define environment variables
 	envVars := map[string]string{
 		"CONTEXT_DIR": p.ContextDirectoryContainer,
 		"VERSION":     p.ImageTag,
 		"IN_DOCKER":   "true",
-		"DISPLAY":     os.Getenv("DISPLAY"),
+	}
+	var env []string
+	for _, key := range slices.Sorted(maps.Keys(envVars)) {
+		if envVars[key] != "" { // Ensure the variable is not empty
+			env = append(env, fmt.Sprintf("%s=%s", key, envVars[key]))
+		}
+	}
+	// Pass through host environment variables by name. The engine reads their value when the command runs.
+	env = append(env, "DISPLAY")
+	env = append(env, matchEnv(p.PassEnv, os.Environ())...)
+	// Fixed values come last, so they take precedence over the passed through variables
+	for _, entry := range p.Env {
+		env = append(env, expandEnv(entry))
+	}
+
+	volumes, err := p.volumes()
+	if err != nil {
+		log.Printf("Skipping volumes: %v", err)
 	}
 
-	// Define volume mappings
+	interactive, tty := p.terminal(os.Stdin, os.Stdout)
+	opts := engine.RunOptions{
+		Image:       p.Image(),
+		Command:     strings.Fields(p.DefaultCommand),
+		Env:         env,
+		Volumes:     volumes,
+		Workdir:     p.Workdir, // Start in the mapped working directory instead of the image's WORKDIR
+		EnvFiles:    p.EnvFiles,
+		Remove:      !p.KeepContainers,
+		Interactive: interactive,
+		TTY:         tty,
+	}
+	p.mapUser(&opts)
+	// Name and label the container after the project, so leftovers can be found by ccli prune
+	if p.ProjectName != "" {
+		opts.Name = fmt.Sprintf("ccli-%s-%d", p.ProjectName, os.Getpid())
+		opts.Labels = []string{fmt.Sprintf("%s=%s", ProjectLabel, p.ProjectName)}
+	}
+	return opts
+}
+
+// volumes returns the volume mappings of the container: the home directory, the working directory and the project
+// volumes. Invalid project volumes are left out, and an error that explains each of them is returned.
+func (p Container) volumes() ([]string, error) {
 	volumes := []string{
 		fmt.Sprintf("%s:%s", p.UserHomeHost, p.UserHomeContainer),                 // Map USER_HOME to /opt/usr/home
 		fmt.Sprintf("%s:%s", p.ContextDirectoryHost, p.ContextDirectoryContainer), // Map CONTEXT_DIR to /opt/context
 	}
+	volumes = slices.DeleteFunc(volumes, func(volume string) bool {
+		return strings.HasPrefix(volume, ":") || strings.HasSuffix(volume, ":") // Skip mappings without a path
+	})
 
-	// Construct the `podman run` command
-	var cmdArgs []string
-	cmdArgs = append(cmdArgs, "run") // Base command: "podman run"
+	var errs []error
+	if p.IsolatedHome {
+		for _, file := range p.HomeFiles {
+			mapping, err := homeFileMapping(file)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if mapping != "" {
+				volumes = append(volumes, mapping)
+			}
+		}
+	}
+	// The manifest comes from the project repository, so with an isolated home it may not mount the home directory
+	for _, volume := range p.ManifestVolumes {
+		if p.IsolatedHome {
+			if err := checkOutsideHome(volume); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		mapping, err := volume.Mapping()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		volumes = append(volumes, mapping)
+	}
+	for _, volume := range p.Volumes {
+		mapping, err := volume.Mapping()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		volumes = append(volumes, mapping)
+	}
+	return volumes, errors.Join(errs...)
+}
 
-	// Add environment variable flags
-	for key, val := range envVars {
-		if val != "" { // Ensure the variable is not empty
-			cmdArgs = append(cmdArgs, "--env", fmt.Sprintf("%s=%s", key, val))
+// checkOutsideHome returns an error if the host path of a volume is the home directory of the user, inside it or one
+// of its parents. Symbolic links are resolved, so a link cannot lead into the home directory either.
+func checkOutsideHome(volume config.VolumeConfig) error {
+	hostPath, err := volume.HostPath()
+	if err != nil || hostPath == "" {
+		return err
+	}
+	home := globals.HomeDir
+	if resolved, err := filepath.EvalSymlinks(home); err == nil {
+		home = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(hostPath); err == nil {
+		hostPath = resolved
+	}
+	inside, _ := filepath.Rel(home, hostPath)
+	parent, _ := filepath.Rel(hostPath, home)
+	if filepath.IsLocal(inside) || filepath.IsLocal(parent) || inside == "." {
+		return fmt.Errorf("volume %s of the manifest exposes the home directory, which is isolated: declare it in "+
+			"the project entry of the config file to mount it", volume.Host)
+	}
+	return nil
+}
+
+// homeFileMapping returns the read-only volume mapping of a path of the user's home directory into the isolated home
+// of the project. Paths that do not exist on this machine are skipped with an empty mapping.
+func homeFileMapping(file string) (string, error) {
+	if !filepath.IsLocal(file) {
+		return "", fmt.Errorf("home file %s: must be a path inside the home directory, relative to it", file)
+	}
+	hostPath := filepath.Join(globals.HomeDir, file)
+	if _, err := os.Stat(hostPath); err != nil {
+		log.Printf("Skipping home file %s: %v", file, err)
+		return "", nil
+	}
+	return fmt.Sprintf("%s:%s:ro", hostPath, path.Join(globals.UserHomeContainer, filepath.ToSlash(file))), nil
+}
+
+// expandEnv expands the ${NAME} references in a fixed value from the host environment. Any other $ is kept as it is,
+// so values such as passwords and prices are passed on unchanged.
+func expandEnv(value string) string {
+	// os.Expand reads $$ as a reference to the variable $, which the mapper turns back into a literal $
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '$' && !strings.HasPrefix(value[i+1:], "{") {
+			escaped.WriteString("$$")
+			continue
+		}
+		escaped.WriteByte(value[i])
+	}
+	return os.Expand(escaped.String(), func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		return os.Getenv(name)
+	})
+}
+
+// matchEnv returns the names of the variables to pass through for the given names and glob patterns. Patterns such as
+// ACME_* are matched against the names of the variables in environ, names without wildcards are kept as they are.
+func matchEnv(patterns []string, environ []string) []string {
+	var names []string
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, "*?[") {
+			names = append(names, pattern)
+			continue
+		}
+		var matches []string
+		for _, entry := range environ {
+			name, _, _ := strings.Cut(entry, "=")
+			if ok, _ := path.Match(pattern, name); ok {
+				matches = append(matches, name)
+			}
 		}
+		slices.Sort(matches)
+		names = append(names, matches...)
 	}
+	return slices.Compact(names)
+}
 
-	// Add volume flags
-	for _, volume := range volumes {
-		if !strings.Contains(volume, ":") || strings.HasPrefix(volume, ":") {
-			continue // Skip invalid volume mappings
+// mapUser maps the host user into the container according to the user mapping of the project, so files written into
+// the mounted directories are owned by the user instead of root. With keep-id, engines other than podman fall back to
+// running the container as the host UID and GID. The auto mapping depends on whether the engine runs rootless, as
+// recorded when it was last probed: rootless podman keeps the user ID, other rootless engines need no mapping and
+// rootful engines run the container as the host UID and GID.
+func (p Container) mapUser(opts *engine.RunOptions) {
+	uid, gid := os.Getuid(), os.Getgid()
+	if uid < 0 { // Windows has no user IDs to map
+		return
+	}
+	user := fmt.Sprintf("%d:%d", uid, gid)
+	switch p.UserMapping {
+	case config.UserMappingNone:
+	case config.UserMappingUser:
+		opts.User = user
+	case config.UserMappingKeepID:
+		opts.User = user
+		opts.KeepUserID = true
+	default:
+		// Files written by root are already owned by the user
+		if uid == 0 {
+			return
 		}
-		cmdArgs = append(cmdArgs, "--volume", volume)
+		var name string
+		if backend, err := p.engine(); err == nil {
+			name = backend.Name()
+		}
+		switch {
+		case p.Rootless && (name == engine.Podman || name == engine.PodmanAPI):
+			opts.KeepUserID = true
+		case p.Rootless:
+			// Root in the container of a rootless engine already is the user
+		default:
+			opts.User = user
+		}
+	}
+}
+
+// terminal decides whether the container keeps stdin open and whether it gets a TTY. Stdin is kept open when it is a
+// terminal, a pipe or a file, so tools can prompt and read piped input. A TTY is allocated when stdin and stdout are
+// both terminals, unless the TTY setting of the project says otherwise.
+func (p Container) terminal(stdin, stdout *os.File) (interactive bool, tty bool) {
+	stdinTerminal := term.IsTerminal(int(stdin.Fd()))
+	if stdinTerminal {
+		interactive = true
+	} else if info, err := stdin.Stat(); err == nil {
+		interactive = info.Mode()&os.ModeCharDevice == 0 // E.g. /dev/null is not worth keeping open
+	}
+	switch p.TTY {
+	case config.TTYAlways:
+		tty = true
+	case config.TTYNever:
+		tty = false
+	default:
+		tty = stdinTerminal && term.IsTerminal(int(stdout.Fd()))
+	}
+	return interactive, tty
+}
+
+// GetRunCommand returns the arguments of the command line client used for running the application.
+func (p Container) GetRunCommand() []string {
+	commandLine, err := p.commandLine()
+	if err != nil {
+		log.Printf("%v", err)
+		return nil
 	}
+	return commandLine.RunArgs(p.RunOptions())
+}
 
-	// Specify the image to run
-	cmdArgs = append(cmdArgs, p.ImageName, p.DefaultCommand)
+// Run runs the application with the given arguments, attached to the standard streams, and returns its exit code.
+// Invalid project volumes fail the run instead of being left out.
+func (p Container) Run(args []string) (int, error) {
+	if _, err := p.volumes(); err != nil {
+		return 1, err
+	}
+	// The isolated home is created before the engine would create it owned by root
+	if p.IsolatedHome {
+		if err := os.MkdirAll(p.UserHomeHost, 0700); err != nil {
+			return 1, fmt.Errorf("failed to create the home directory of %s: %w", p.ProjectName, err)
+		}
+	}
+	opts := p.RunOptions()
+	opts.Command = append(opts.Command, args...)
+	backend, err := p.engine()
+	if err != nil {
+		return 1, err
+	}
+	return backend.Run(opts)
+}
 
-	// Print the full command for logging or debugging
-	commandStr := fmt.Sprintf("%s %s", p.ContainerEngine, strings.Join(cmdArgs, " "))
-	fmt.Println("Generated Command:", commandStr)
+// BuildOptions returns the options used for building the image. Build argument values are expanded from the host
+// environment, so ${VAR} references can be used for values such as internal mirror URLs. Any other $ is kept.
+func (p Container) BuildOptions() engine.BuildOptions {
+	tags := []string{p.ImageName}
+	if p.CommitTag != "" {
+		tags = append(tags, fmt.Sprintf("%s:%s", p.ImageName, p.CommitTag))
+	}
+	var buildArgs []string
+	for _, buildArg := range p.BuildArgs {
+		buildArgs = append(buildArgs, expandEnv(buildArg))
+	}
+	return engine.BuildOptions{
+		Dockerfile: p.Dockerfile,
+		Context:    p.BuildContext,
+		Tags:       tags,
+		BuildArgs:  buildArgs,
+		Target:     p.Target,
+		Labels:     p.Labels,
+		Platform:   p.Platform,
+	}
+}
 
-	return cmdArgs
+// GetBuildCommand returns the arguments of the command line client used for building the image.
+func (p Container) GetBuildCommand() []string {
+	commandLine, err := p.commandLine()
+	if err != nil {
+		log.Printf("%v", err)
+		return nil
+	}
+	return commandLine.BuildArgs(p.BuildOptions())
 }
 
-// Build builds a Podman image using the specified Dockerfile, image name, and build context.
-func (p Container) Build() error {
-	// build using shell for now
-	// come back and use go for this later...maybe...
-	err := buildImageUsingShell(p.ContainerEngine, p.Dockerfile, p.ImageName, p.BuildContext)
+// Build builds the container image using the specified Dockerfile, image name, and build context. The build output
+// is written to output as it is produced.
+func (p Container) Build(output io.Writer) error {
+	opts := p.BuildOptions()
+	opts.Output = output
+	backend, err := p.engine()
 	if err != nil {
 		return err
 	}
-	return nil
+	return backend.Build(opts)
 }
 
-// buildImageUsingShell performs a Podman build by invoking the host shell.
-func buildImageUsingShell(engine, dockerfilePath, imageName, contextDir string) error {
-	// Construct the command arguments for `podman build`
-	cmdArgs := []string{
-		"build",
-		"-f", dockerfilePath, // Specify the Dockerfile path
-		"-t", imageName, // Assign the tag to the image
-		contextDir, // Set the build context (working directory)
+// Pull pulls the prebuilt image from its registry. The progress is written to output as it arrives.
+func (p Container) Pull(output io.Writer) error {
+	backend, err := p.engine()
+	if err != nil {
+		return err
 	}
+	return backend.Pull(p.Image(), output)
+}
 
-	// Create a new Podman build command
-	cmd := exec.Command(engine, cmdArgs...)
+// ImageExists reports whether the container image for this container is present in the local image store.
+func (p Container) ImageExists() bool {
+	backend, err := p.engine()
+	return err == nil && backend.ImageExists(p.Image())
+}
 
-	// Capture the standard output and standard error
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// ImageID returns the ID of the container image in the local image store.
+func (p Container) ImageID() (string, error) {
+	backend, err := p.engine()
+	if err != nil {
+		return "", err
+	}
+	info, err := backend.Inspect(p.Image())
+	if err != nil {
+		return "", err
+	}
+	return info.ID, nil
+}
 
-	// Set the working directory to the context directory
-	cmd.Dir = contextDir
+// ImageDigest returns the registry digest of the container image, or an empty string for images that were built
+// locally and never pushed or pulled.
+func (p Container) ImageDigest() (string, error) {
+	backend, err := p.engine()
+	if err != nil {
+		return "", err
+	}
+	info, err := backend.Inspect(p.Image())
+	if err != nil {
+		return "", err
+	}
+	if len(info.RepoDigests) == 0 {
+		return "", nil
+	}
+	return info.RepoDigests[0], nil
+}
 
-	// Execute the Podman command
-	log.Printf("Running command: podman %v\n", cmdArgs)
-	err := cmd.Run()
+// RemoveImage removes the container image for this container from the local image store.
+func (p Container) RemoveImage() error {
+	backend, err := p.engine()
+	if err != nil {
+		return err
+	}
+	return backend.RemoveImage(p.Image())
+}
 
-	// Print any standard output and error
-	if stdout.Len() > 0 {
-		fmt.Printf("Output:\n%s\n", stdout.String())
+// engine returns the engine backend of the container. Containers that were not created by NewContainer use the engine
+// named by ContainerEngine, or docker when no engine is named. An unknown engine is an error.
+func (p Container) engine() (engine.Engine, error) {
+	if p.Engine != nil {
+		return p.Engine, nil
 	}
-	if stderr.Len() > 0 {
-		fmt.Printf("Error Output:\n%s\n", stderr.String())
+	if p.ContainerEngine == "" {
+		return engine.NewDocker(), nil
 	}
+	return engine.New(p.ContainerEngine)
+}
 
-	// Check for errors in running the command
+// commandLine returns the command line client of the container's engine. Engines without one are shown with the
+// arguments docker would use.
+func (p Container) commandLine() (engine.CommandLine, error) {
+	backend, err := p.engine()
 	if err != nil {
-		return fmt.Errorf("failed to execute podman build: %w", err)
+		return nil, err
 	}
-
-	log.Println("Podman image built successfully!")
-	return nil
+	if commandLine, ok := backend.(engine.CommandLine); ok {
+		return commandLine, nil
+	}
+	return engine.NewDocker().(engine.CommandLine), nil
 }
 
-// NewContainer initializes and returns a Container object configured with the given project settings.
-func NewContainer(projectConfig *config.ProjectConfig) Container {
+// NewContainer initializes and returns a Container object configured with the given project settings. It fails when
+// no container engine is set or the engine of the project or the config file is unknown.
+func NewContainer(projectConfig *config.ProjectConfig) (Container, error) {
 	workingDir, err := os.Getwd() // Returns the directory from where the program is invoked
 	if err != nil {
 		log.Fatal(err)
 	}
 	homeDir, _ := os.UserHomeDir()
-	containerEngine, err := config.GetContainerEngineFromConfig()
+	// The config file holds the global engine and the environment defaults that apply to every project
+	globalConfig, err := config.LoadConfig()
+	if err != nil {
+		log.Printf("Ignoring config file: %v", err)
+		globalConfig = &config.ContainerCliConfig{}
+	}
+	// A project can use a different container engine than the one set globally
+	containerEngine := projectConfig.Engine
+	if containerEngine == "" {
+		containerEngine = globalConfig.ContainerEngine
+	}
+	if containerEngine == "" {
+		return Container{}, fmt.Errorf("no container engine is set for %s: run ccli install", projectConfig.Name)
+	}
+	backend, err := engine.New(containerEngine)
+	if err != nil {
+		return Container{}, fmt.Errorf("project %s: %w", projectConfig.Name, err)
+	}
+	// Settings declared in the project manifest come first so the project configuration can extend them
+	manifest, err := config.LoadManifest(projectConfig.Path)
 	if err != nil {
-		fmt.Println("failed to get container engine from config: ", err)
-		panic(err)
+		log.Printf("Ignoring project manifest: %v", err)
+		manifest = &config.Manifest{}
+	}
+	// A prebuilt image from the project configuration or the manifest replaces the locally built image
+	imageName := projectConfig.Name
+	image := projectConfig.Image
+	if image == "" {
+		image = manifest.Image
+	}
+	if image != "" {
+		imageName = image
+	}
+	imageTag := projectConfig.ImageTag
+	if imageTag == "" {
+		imageTag = "latest"
+	}
+	// The TTY setting of the project configuration overrides the one of the manifest
+	tty := projectConfig.TTY
+	if tty == "" {
+		tty = manifest.TTY
+	}
+	var envFiles []string
+	for _, envFile := range []string{globalConfig.EnvFile, projectConfig.EnvFile} {
+		if envFile == "" {
+			continue
+		}
+		expanded, err := utils.ExpandPath(envFile)
+		if err != nil {
+			log.Printf("Skipping env file %s: %v", envFile, err)
+			continue
+		}
+		envFiles = append(envFiles, expanded)
+	}
+	// An isolated home replaces the home directory of the user with a private directory of the project
+	home := projectConfig.Home
+	if home == "" {
+		home = manifest.Home
+	}
+	isolatedHome := home == config.HomeIsolated
+	if isolatedHome {
+		homeDir = filepath.Join(globals.HomesDirectory, projectConfig.Name)
+	}
+	// The user mapping of the project configuration overrides the one of the manifest
+	userMapping := projectConfig.UserMapping
+	if userMapping == "" {
+		userMapping = manifest.UserMapping
+	}
+	// The container starts in the mapped working directory unless the project keeps the image's WORKDIR
+	workdir := projectConfig.Workdir
+	if workdir == "" {
+		workdir = manifest.Workdir
+	}
+	switch workdir {
+	case "":
+		workdir = globals.ContextDirectoryContainer
+	case config.WorkdirImage:
+		workdir = ""
 	}
 	return Container{
+		Engine:                    backend,
+		ProjectName:               projectConfig.Name,
 		BuildContext:              projectConfig.BuildContext,
 		BuildDirectory:            projectConfig.BuildDirectory,
 		ContainerEngine:           containerEngine,
 		ContextDirectoryContainer: globals.ContextDirectoryContainer,
 		ContextDirectoryHost:      workingDir,
 		Dockerfile:                projectConfig.Dockerfile,
-		ImageName:                 projectConfig.Name,
-		ImageTag:                  "latest",
+		ImageName:                 imageName,
+		ImageTag:                  imageTag,
+		CommitTag:                 ShortCommit(projectConfig.Commit),
+		Prebuilt:                  image != "",
 		UserHomeContainer:         globals.UserHomeContainer,
 		UserHomeHost:              homeDir,
+		IsolatedHome:              isolatedHome,
+		HomeFiles:                 slices.Concat(manifest.HomeFiles, projectConfig.HomeFiles),
 		DefaultCommand:            projectConfig.DefaultCommand,
-	}
+		BuildArgs:                 append(utils.CopySlice(manifest.BuildArgs), projectConfig.BuildArgs...),
+		Target:                    projectConfig.Target,
+		Labels:                    projectConfig.Labels,
+		Platform:                  projectConfig.Platform,
+		Volumes:                   projectConfig.Volumes,
+		ManifestVolumes:           manifest.Volumes,
+		PassEnv:                   slices.Concat(globalConfig.PassEnv, manifest.PassEnv, projectConfig.PassEnv),
+		Env:                       slices.Concat(globalConfig.Env, manifest.Env, projectConfig.Env),
+		EnvFiles:                  envFiles,
+		Workdir:                   workdir,
+		TTY:                       tty,
+		KeepContainers:            projectConfig.KeepContainers,
+		UserMapping:               userMapping,
+		Rootless:                  slices.Contains(globalConfig.RootlessEngines, containerEngine),
+	}, nil
 }
@@ -0,0 +1,39 @@
+package container
+
+import (
+	"bytes"
+	"io"
+)
+
+// PrefixWriter writes every line it receives to an underlying writer, prefixed with a fixed string. Partial lines are
+// written as they arrive, so progress output is not held back until the line ends.
+type PrefixWriter struct {
+	w       io.Writer
+	prefix  []byte
+	midLine bool // The last write did not end with a newline
+}
+
+// NewPrefixWriter returns a PrefixWriter that writes to w and starts every line with prefix.
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{w: w, prefix: []byte(prefix)}
+}
+
+// Write writes p to the underlying writer, inserting the prefix at the start of every line.
+func (pw *PrefixWriter) Write(p []byte) (int, error) {
+	var out bytes.Buffer
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if !pw.midLine {
+			out.Write(pw.prefix)
+		}
+		out.Write(line)
+		pw.midLine = line[len(line)-1] != '\n'
+	}
+	_, err := pw.w.Write(out.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
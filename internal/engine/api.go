@@ -0,0 +1,781 @@
+package engine
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/go-version"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+	"golang.org/x/term"
+)
+
+// api is an Engine that talks to the Docker Engine HTTP API, or the compatible API of podman, over a unix socket or
+// TCP. Unlike the command line clients, the output of builds and pulls is streamed as it arrives.
+type api struct {
+	name       string
+	host       string // E.g. unix:///var/run/docker.sock or tcp://127.0.0.1:2375
+	keepUserID bool   // The API accepts keep-id as user namespace mode. Only podman supports it
+	version    string // API version requests are sent to, negotiated with the engine on the first request
+	client     *http.Client
+}
+
+// apiBaseURL is the URL requests are sent to. The host part is ignored when dialing a unix socket.
+const apiBaseURL = "http://engine"
+
+// maxAPIVersion is the newest API version ccli talks. Engines that support an older version only are talked to in
+// their version, and engines that do not tell their version in the older version every engine still supports.
+const (
+	maxAPIVersion      = "1.44"
+	fallbackAPIVersion = "1.24"
+)
+
+// NewDockerAPI returns the engine for the Docker Engine API at DOCKER_HOST, or the default docker socket.
+func NewDockerAPI() Engine {
+	return NewAPI(DockerAPI, DefaultHost(DockerAPI), false)
+}
+
+// NewPodmanAPI returns the engine for the docker compatible API of podman at DOCKER_HOST, or the podman socket of the
+// current user.
+func NewPodmanAPI() Engine {
+	return NewAPI(PodmanAPI, DefaultHost(PodmanAPI), true)
+}
+
+// NewAPI returns an engine for the Docker Engine API at the given host.
+func NewAPI(name, host string, keepUserID bool) Engine {
+	return &api{
+		name:       name,
+		host:       host,
+		keepUserID: keepUserID,
+		client:     &http.Client{Transport: &http.Transport{DialContext: dialer(host)}},
+	}
+}
+
+// DefaultHost returns the host of the API engine with the given name. DOCKER_HOST takes precedence over the default
+// sockets.
+func DefaultHost(name string) string {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return host
+	}
+	if name == PodmanAPI {
+		if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" && os.Getuid() != 0 {
+			return "unix://" + filepath.Join(runtimeDir, "podman", "podman.sock")
+		}
+		return "unix:///run/podman/podman.sock"
+	}
+	return "unix:///var/run/docker.sock"
+}
+
+// dialer returns a function that connects to the given host. unix:// hosts are dialed as sockets, tcp:// and
+// http:// hosts over TCP.
+func dialer(host string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		if socket, ok := strings.CutPrefix(host, "unix://"); ok {
+			return d.DialContext(ctx, "unix", socket)
+		}
+		address := strings.TrimPrefix(strings.TrimPrefix(host, "tcp://"), "http://")
+		return d.DialContext(ctx, "tcp", address)
+	}
+}
+
+// Name returns the name of the engine.
+func (a *api) Name() string {
+	return a.name
+}
+
+// negotiateVersion pings the API for the newest version the engine supports, and keeps the older one of it and
+// maxAPIVersion. A failed ping is not remembered, so the next request tries again.
+func (a *api) negotiateVersion() string {
+	if a.version != "" {
+		return a.version
+	}
+	resp, err := a.client.Get(apiBaseURL + "/_ping")
+	if err != nil {
+		return maxAPIVersion
+	}
+	_ = resp.Body.Close()
+	a.version = fallbackAPIVersion
+	engineVersion, err := version.NewVersion(resp.Header.Get("Api-Version"))
+	if err == nil {
+		a.version = maxAPIVersion
+		if engineVersion.LessThan(version.Must(version.NewVersion(maxAPIVersion))) {
+			a.version = engineVersion.Original()
+		}
+	}
+	return a.version
+}
+
+// url returns the URL of an API endpoint in the negotiated version.
+func (a *api) url(path string, query url.Values) string {
+	target := apiBaseURL + "/v" + a.negotiateVersion() + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return target
+}
+
+// do sends a request to the API and returns the response. Responses with an error status are turned into errors
+// carrying the message of the API.
+func (a *api) do(method, path string, query url.Values, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, a.url(path, query), body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s at %s: %w", a.name, a.host, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, apiError(resp)
+	}
+	return resp, nil
+}
+
+// doJSON sends a request with a JSON body and decodes the JSON response into out, if out is not nil.
+func (a *api) doJSON(method, path string, query url.Values, in, out any) error {
+	var body io.Reader
+	contentType := ""
+	if in != nil {
+		encoded, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+		contentType = "application/json"
+	}
+	resp, err := a.do(method, path, query, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// apiError returns the error message of an API response.
+func apiError(resp *http.Response) error {
+	var message struct {
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(data, &message) == nil && message.Message != "" {
+		return fmt.Errorf("%s", message.Message)
+	}
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+}
+
+// streamMessage is a message of the JSON stream returned by builds and pulls.
+type streamMessage struct {
+	Stream string `json:"stream"`
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+// readStream writes the messages of a JSON stream to w as they arrive and returns the first error message.
+func readStream(r io.Reader, w io.Writer) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var message streamMessage
+		err := decoder.Decode(&message)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if message.Error != "" {
+			return fmt.Errorf("%s", message.Error)
+		}
+		if message.Stream != "" {
+			_, _ = io.WriteString(w, message.Stream)
+		} else if message.Status != "" {
+			_, _ = fmt.Fprintln(w, message.Status)
+		}
+	}
+}
+
+// Build sends the build context as a tar archive and streams the build output.
+func (a *api) Build(opts BuildOptions) error {
+	dockerfile, err := filepath.Rel(opts.Context, opts.Dockerfile)
+	if err != nil || strings.HasPrefix(dockerfile, "..") {
+		return fmt.Errorf("the Dockerfile %s must be inside the build context %s", opts.Dockerfile, opts.Context)
+	}
+	query := url.Values{"dockerfile": {filepath.ToSlash(dockerfile)}, "t": opts.Tags}
+	if len(opts.BuildArgs) > 0 {
+		query.Set("buildargs", keyValueJSON(opts.BuildArgs))
+	}
+	if len(opts.Labels) > 0 {
+		query.Set("labels", keyValueJSON(opts.Labels))
+	}
+	if opts.Target != "" {
+		query.Set("target", opts.Target)
+	}
+	if opts.Platform != "" {
+		query.Set("platform", opts.Platform)
+	}
+
+	// Stream the archive while it is written
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(writeContext(writer, opts.Context, filepath.ToSlash(dockerfile)))
+	}()
+	log.Printf("Building %v with %s", opts.Tags, a.name)
+	resp, err := a.do(http.MethodPost, "/build", query, "application/x-tar", reader)
+	if err != nil {
+		_ = reader.CloseWithError(err)
+		return fmt.Errorf("failed to execute %s build: %w", a.name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	err = readStream(resp.Body, buildOutput(opts))
+	if err != nil {
+		return fmt.Errorf("failed to execute %s build: %w", a.name, err)
+	}
+	log.Printf("%s image built successfully!", a.name)
+	return nil
+}
+
+// keyValueJSON encodes KEY=VALUE pairs as the JSON object expected by the build endpoint.
+func keyValueJSON(pairs []string) string {
+	encoded, _ := json.Marshal(keyValues(pairs))
+	return string(encoded)
+}
+
+// keyValues turns KEY=VALUE pairs into a map, or nil when there are none.
+func keyValues(pairs []string) map[string]string {
+	if len(pairs) == 0 {
+		return nil
+	}
+	values := map[string]string{}
+	for _, pair := range pairs {
+		key, value, _ := strings.Cut(pair, "=")
+		values[key] = value
+	}
+	return values
+}
+
+// writeContext writes the files of the build context directory to w as a tar archive. Files matched by the
+// .dockerignore file of the context are left out, except for the Dockerfile and the .dockerignore file itself, which
+// the engine always needs.
+func writeContext(w io.Writer, dir, dockerfile string) error {
+	ignored, err := readDockerignore(dir, dockerfile)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(w)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == "." {
+			return err
+		}
+		if ignored != nil {
+			skip, err := ignored.MatchesOrParentMatches(filepath.ToSlash(name))
+			if err != nil {
+				return err
+			}
+			if skip {
+				// Directories are still walked when an exception may include some of their files
+				if info.IsDir() && !ignored.Exclusions() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		err = tw.WriteHeader(header)
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// readDockerignore returns the matcher of the .dockerignore file in the build context directory, or nil when there is
+// none. The Dockerfile and the .dockerignore file are never ignored.
+func readDockerignore(dir, dockerfile string) (*patternmatcher.PatternMatcher, error) {
+	file, err := os.Open(filepath.Join(dir, ".dockerignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	patterns, err := ignorefile.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the .dockerignore file of %s: %w", dir, err)
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	return patternmatcher.New(append(patterns, "!"+dockerfile, "!.dockerignore"))
+}
+
+// containerConfig is the body of the container create endpoint.
+type containerConfig struct {
+	Image        string
+	Cmd          []string          `json:",omitempty"`
+	Env          []string          `json:",omitempty"`
+	WorkingDir   string            `json:",omitempty"`
+	User         string            `json:",omitempty"`
+	Labels       map[string]string `json:",omitempty"`
+	Tty          bool              `json:",omitempty"`
+	AttachStdin  bool              `json:",omitempty"`
+	AttachStdout bool              `json:",omitempty"`
+	AttachStderr bool              `json:",omitempty"`
+	OpenStdin    bool              `json:",omitempty"`
+	StdinOnce    bool              `json:",omitempty"`
+	HostConfig   hostConfig
+}
+
+// hostConfig holds the host settings of a container.
+type hostConfig struct {
+	Binds      []string `json:",omitempty"`
+	UsernsMode string   `json:",omitempty"`
+}
+
+// Run creates a container, attaches to it, starts it and waits for it to exit.
+func (a *api) Run(opts RunOptions) (int, error) {
+	stdin, stdout, stderr := opts.Stdin, opts.Stdout, opts.Stderr
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	// A TTY is only allocated when the output goes to a terminal, which then reports its size to the container
+	stdinFd, stdinTerminal := terminalFd(stdin)
+	stdoutFd, stdoutTerminal := terminalFd(stdout)
+	tty := opts.TTY && stdoutTerminal
+
+	env, err := apiEnv(opts.EnvFiles, opts.Env)
+	if err != nil {
+		return 1, err
+	}
+	containerCfg := containerConfig{
+		Image:        opts.Image,
+		Cmd:          opts.Command,
+		Env:          env,
+		WorkingDir:   opts.Workdir,
+		User:         opts.User,
+		Labels:       keyValues(opts.Labels),
+		Tty:          tty,
+		AttachStdin:  opts.Interactive,
+		AttachStdout: true,
+		AttachStderr: true,
+		OpenStdin:    opts.Interactive,
+		StdinOnce:    opts.Interactive,
+		HostConfig:   hostConfig{Binds: opts.Volumes},
+	}
+	if opts.KeepUserID && a.keepUserID {
+		containerCfg.User = ""
+		containerCfg.HostConfig.UsernsMode = "keep-id"
+	}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	var query url.Values
+	if opts.Name != "" {
+		query = url.Values{"name": {opts.Name}}
+	}
+	err = a.doJSON(http.MethodPost, "/containers/create", query, containerCfg, &created)
+	if err != nil {
+		return 1, fmt.Errorf("failed to create a container for %s: %w", opts.Image, err)
+	}
+
+	// The container is removed once it exited and its exit code was read, instead of having the engine remove it,
+	// which could happen before the exit code is read
+	if opts.Remove {
+		defer func() {
+			err := a.RemoveContainer(created.ID)
+			if err != nil {
+				log.Printf("%v", err)
+			}
+		}()
+	}
+	// Interrupts are passed to the container like docker run does, so ccli keeps running until the container exited
+	// and was removed. A signal that arrives before the container started is passed once it started.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+
+	conn, output, err := a.attach(created.ID, opts.Interactive)
+	if err != nil {
+		return 1, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	err = a.doJSON(http.MethodPost, "/containers/"+url.PathEscape(created.ID)+"/start", nil, nil, nil)
+	if err != nil {
+		return 1, fmt.Errorf("failed to start the container for %s: %w", opts.Image, err)
+	}
+	go func() {
+		for sig := range signals {
+			a.kill(created.ID, sig)
+		}
+	}()
+	if tty {
+		a.resize(created.ID, stdoutFd)
+		// The terminal passes every key to the container, which echoes and edits the input itself
+		if opts.Interactive && stdinTerminal {
+			state, err := term.MakeRaw(stdinFd)
+			if err != nil {
+				return 1, fmt.Errorf("failed to put the terminal into raw mode: %w", err)
+			}
+			defer func() { _ = term.Restore(stdinFd, state) }()
+		}
+	}
+
+	// Copy stdin until it ends, then signal the end of the input to the container
+	if opts.Interactive {
+		go func() {
+			_, _ = io.Copy(conn, stdin)
+			if closer, ok := conn.(interface{ CloseWrite() error }); ok {
+				_ = closer.CloseWrite()
+			}
+		}()
+	}
+	// With a TTY the output is a single raw stream, otherwise stdout and stderr are multiplexed
+	if tty {
+		_, err = io.Copy(stdout, output)
+	} else {
+		err = demux(output, stdout, stderr)
+	}
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		return 1, fmt.Errorf("failed to read the output of %s: %w", opts.Image, err)
+	}
+
+	var waited struct {
+		StatusCode int
+	}
+	err = a.doJSON(http.MethodPost, "/containers/"+url.PathEscape(created.ID)+"/wait", nil, nil, &waited)
+	if err != nil {
+		return 1, fmt.Errorf("failed to wait for the container of %s: %w", opts.Image, err)
+	}
+	return waited.StatusCode, nil
+}
+
+// kill sends a signal to the process of a container. A container that already exited cannot be signalled, so a
+// failure is logged instead of returned.
+func (a *api) kill(id string, sig os.Signal) {
+	name := "SIGTERM"
+	if sig == os.Interrupt {
+		name = "SIGINT"
+	}
+	query := url.Values{"signal": {name}}
+	err := a.doJSON(http.MethodPost, "/containers/"+url.PathEscape(id)+"/kill", query, nil, nil)
+	if err != nil {
+		log.Printf("Failed to send %s to container %s: %v", name, id, err)
+	}
+}
+
+// terminalFd returns the file descriptor of a stream and whether it is a terminal.
+func terminalFd(stream any) (int, bool) {
+	file, ok := stream.(*os.File)
+	if !ok {
+		return 0, false
+	}
+	fd := int(file.Fd())
+	return fd, term.IsTerminal(fd)
+}
+
+// resize sets the TTY of a container to the size of the terminal. A failed resize only leaves the TTY at the default
+// size of the engine, so it is logged instead of failing the run.
+func (a *api) resize(id string, fd int) {
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		log.Printf("Failed to read the terminal size: %v", err)
+		return
+	}
+	query := url.Values{"h": {fmt.Sprint(height)}, "w": {fmt.Sprint(width)}}
+	err = a.doJSON(http.MethodPost, "/containers/"+url.PathEscape(id)+"/resize", query, nil, nil)
+	if err != nil {
+		log.Printf("Failed to resize the TTY of container %s: %v", id, err)
+	}
+}
+
+// apiEnv turns the environment files and variables of the run options into KEY=VALUE pairs, the variables of the
+// files first. Variables that are passed through by name take their value from the environment of ccli, and are left
+// out when they are not set.
+func apiEnv(envFiles, env []string) ([]string, error) {
+	var entries []string
+	for _, envFile := range envFiles {
+		fileEntries, err := readEnvFile(envFile)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	entries = append(entries, env...)
+
+	var pairs []string
+	for _, entry := range entries {
+		if strings.Contains(entry, "=") {
+			pairs = append(pairs, entry)
+			continue
+		}
+		if value, ok := os.LookupEnv(entry); ok {
+			pairs = append(pairs, entry+"="+value)
+		}
+	}
+	return pairs, nil
+}
+
+// readEnvFile reads an environment file in the format of docker's --env-file: one KEY=VALUE or NAME per line, with
+// empty lines and lines starting with # ignored. Values are taken literally.
+func readEnvFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	var entries []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimLeft(strings.TrimSuffix(line, "\r"), " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return entries, nil
+}
+
+// attach attaches to the output of a container, and to its input when interactive is set. The API upgrades the HTTP connection to a raw stream, so the request
+// is written to a connection of its own and the connection is returned together with a reader of the output.
+func (a *api) attach(id string, interactive bool) (net.Conn, io.Reader, error) {
+	conn, err := dialer(a.host)(context.Background(), "", "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to reach %s at %s: %w", a.name, a.host, err)
+	}
+	query := url.Values{"stream": {"1"}, "stdout": {"1"}, "stderr": {"1"}}
+	if interactive {
+		query.Set("stdin", "1")
+	}
+	req, err := http.NewRequest(http.MethodPost, a.url("/containers/"+url.PathEscape(id)+"/attach", query), nil)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "tcp")
+	err = req.Write(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("failed to attach to container %s: %w", id, err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("failed to attach to container %s: %w", id, err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols && resp.StatusCode != http.StatusOK {
+		defer func() { _ = conn.Close() }()
+		return nil, nil, fmt.Errorf("failed to attach to container %s: %w", id, apiError(resp))
+	}
+	return conn, reader, nil
+}
+
+// demux copies the multiplexed output of a container to stdout and stderr. Every frame starts with an 8 byte header
+// holding the stream in the first byte and the size of the frame in the last four.
+func demux(r io.Reader, stdout, stderr io.Writer) error {
+	header := make([]byte, 8)
+	for {
+		_, err := io.ReadFull(r, header)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		w := stdout
+		if header[0] == 2 {
+			w = stderr
+		}
+		_, err = io.CopyN(w, r, int64(binary.BigEndian.Uint32(header[4:])))
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ListContainers returns every container of the engine, including stopped ones.
+func (a *api) ListContainers() ([]ContainerInfo, error) {
+	resp, err := a.do(http.MethodGet, "/containers/json", url.Values{"all": {"1"}}, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s containers: %w", a.name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s containers: %w", a.name, err)
+	}
+	return parseContainerList(data)
+}
+
+// parseContainerList parses the JSON array of containers returned by the API, which podman ps also prints.
+func parseContainerList(data []byte) ([]ContainerInfo, error) {
+	var entries []struct {
+		ID     string `json:"Id"`
+		Names  []string
+		Image  string
+		State  string
+		Labels map[string]string
+	}
+	err := json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the container list: %w", err)
+	}
+	containers := make([]ContainerInfo, 0, len(entries))
+	for _, entry := range entries {
+		name := ""
+		if len(entry.Names) > 0 {
+			name = strings.TrimPrefix(entry.Names[0], "/")
+		}
+		containers = append(containers, ContainerInfo{
+			ID:     entry.ID,
+			Name:   name,
+			Image:  entry.Image,
+			State:  entry.State,
+			Labels: entry.Labels,
+		})
+	}
+	return containers, nil
+}
+
+// RemoveContainer removes a stopped container.
+func (a *api) RemoveContainer(id string) error {
+	err := a.doJSON(http.MethodDelete, "/containers/"+url.PathEscape(id), nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove container %s: %w", id, err)
+	}
+	return nil
+}
+
+// Pull pulls an image from its registry and streams the progress.
+func (a *api) Pull(image string, output io.Writer) error {
+	log.Printf("Pulling %s with %s", image, a.name)
+	query := url.Values{"fromImage": {image}}
+	// The API pulls every tag of a repository when none is given, the docker CLI pulls latest
+	name := image[strings.LastIndex(image, "/")+1:]
+	if !strings.ContainsAny(name, ":@") {
+		query.Set("tag", "latest")
+	}
+	resp, err := a.do(http.MethodPost, "/images/create", query, "", nil)
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", image, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if output == nil {
+		output = os.Stdout
+	}
+	err = readStream(resp.Body, output)
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", image, err)
+	}
+	return nil
+}
+
+// RemoveImage removes an image from the local image store.
+func (a *api) RemoveImage(image string) error {
+	err := a.doJSON(http.MethodDelete, "/images/"+url.PathEscape(image), nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove image %s: %w", image, err)
+	}
+	return nil
+}
+
+// ImageExists reports whether an image is present in the local image store.
+func (a *api) ImageExists(image string) bool {
+	_, err := a.Inspect(image)
+	return err == nil
+}
+
+// Inspect returns the details of an image in the local image store.
+func (a *api) Inspect(image string) (*ImageInfo, error) {
+	var info ImageInfo
+	err := a.doJSON(http.MethodGet, "/images/"+url.PathEscape(image)+"/json", nil, nil, &info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+	return &info, nil
+}
+
+// Probe pings the API and reads its info to tell whether the engine runs rootless. A unix socket that does not exist
+// counts as an engine that is not installed.
+func (a *api) Probe() Status {
+	status := Status{Name: a.name, Path: a.host, Installed: true}
+	if socket, ok := strings.CutPrefix(a.host, "unix://"); ok {
+		if _, err := os.Stat(socket); err != nil {
+			status.Installed = false
+			status.Err = fmt.Errorf("%s is not installed: the socket %s does not exist", a.name, socket)
+			return status
+		}
+	}
+	// Probes do not wait for an engine that does not answer
+	probe := *a
+	probe.client = &http.Client{Transport: a.client.Transport, Timeout: probeTimeout}
+	var info struct {
+		SecurityOptions []string
+	}
+	err := probe.doJSON(http.MethodGet, "/info", nil, nil, &info)
+	if err != nil {
+		status.Err = fmt.Errorf("%s is installed but not running: %w", a.name, err)
+		return status
+	}
+	status.Running = true
+	status.Rootless = rootlessSecurityOption(strings.Join(info.SecurityOptions, "\n"))
+	return status
+}
@@ -0,0 +1,261 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// cli is an Engine that runs the docker compatible command line client of a container engine. The engines differ in
+// a few run flags, which are added by userArgs, and in how their info reports a rootless engine.
+type cli struct {
+	name           string
+	binary         string
+	userArgs       func(opts RunOptions) []string
+	rootlessFormat string            // Go template passed to the info command when probing the engine
+	rootless       func(string) bool // Reports whether the output of the info command marks the engine as rootless
+	listContainers func(binary string) ([]ContainerInfo, error)
+}
+
+// Name returns the name of the engine.
+func (c cli) Name() string {
+	return c.name
+}
+
+// BuildArgs returns the arguments of the build command.
+func (c cli) BuildArgs(opts BuildOptions) []string {
+	args := []string{"build", "-f", opts.Dockerfile}
+	for _, tag := range opts.Tags {
+		args = append(args, "-t", tag)
+	}
+	for _, buildArg := range opts.BuildArgs {
+		args = append(args, "--build-arg", buildArg)
+	}
+	if opts.Target != "" {
+		args = append(args, "--target", opts.Target)
+	}
+	for _, label := range opts.Labels {
+		args = append(args, "--label", label)
+	}
+	if opts.Platform != "" {
+		args = append(args, "--platform", opts.Platform)
+	}
+	return append(args, opts.Context)
+}
+
+// RunArgs returns the arguments of the run command.
+func (c cli) RunArgs(opts RunOptions) []string {
+	args := []string{"run"}
+	if opts.Interactive {
+		args = append(args, "--interactive")
+	}
+	if opts.TTY {
+		args = append(args, "--tty")
+	}
+	if opts.Remove {
+		args = append(args, "--rm")
+	}
+	if opts.Name != "" {
+		args = append(args, "--name", opts.Name)
+	}
+	for _, label := range opts.Labels {
+		args = append(args, "--label", label)
+	}
+	for _, envFile := range opts.EnvFiles {
+		args = append(args, "--env-file", envFile)
+	}
+	for _, env := range opts.Env {
+		args = append(args, "--env", env)
+	}
+	for _, volume := range opts.Volumes {
+		args = append(args, "--volume", volume)
+	}
+	if c.userArgs != nil {
+		args = append(args, c.userArgs(opts)...)
+	}
+	if opts.Workdir != "" {
+		args = append(args, "--workdir", opts.Workdir)
+	}
+	args = append(args, opts.Image)
+	return append(args, opts.Command...)
+}
+
+// Build builds an image, streaming the standard output and standard error of the client to the build output in the
+// order they are written.
+func (c cli) Build(opts BuildOptions) error {
+	cmd := exec.Command(c.binary, c.BuildArgs(opts)...)
+	cmd.Stdout = buildOutput(opts)
+	cmd.Stderr = cmd.Stdout
+
+	// Set the working directory to the context directory
+	cmd.Dir = opts.Context
+
+	log.Printf("Running command: %s %v\n", c.binary, cmd.Args[1:])
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("failed to execute %s build: %w", c.name, err)
+	}
+	log.Printf("%s image built successfully!", c.name)
+	return nil
+}
+
+// Run runs a container attached to the given streams and returns its exit code.
+func (c cli) Run(opts RunOptions) (int, error) {
+	args := c.RunArgs(opts)
+	log.Printf("Generated Command: %s %s", c.binary, strings.Join(args, " "))
+	cmd := exec.Command(c.binary, args...)
+	cmd.Stdin = opts.Stdin
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stdout = opts.Stdout
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = opts.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, fmt.Errorf("failed to run %s: %w", opts.Image, err)
+	}
+	return 0, nil
+}
+
+// Pull pulls an image from its registry, writing the standard output and standard error of the client to output.
+func (c cli) Pull(image string, output io.Writer) error {
+	cmd := exec.Command(c.binary, "pull", image)
+	cmd.Stdout = output
+	if output == nil {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = cmd.Stdout
+	log.Printf("Running command: %s pull %s\n", c.binary, image)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", image, err)
+	}
+	return nil
+}
+
+// RemoveImage removes an image from the local image store.
+func (c cli) RemoveImage(image string) error {
+	cmd := exec.Command(c.binary, "image", "rm", image)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to remove image %s: %w: %s", image, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ImageExists reports whether an image is present in the local image store.
+func (c cli) ImageExists(image string) bool {
+	cmd := exec.Command(c.binary, "image", "inspect", image)
+	return cmd.Run() == nil
+}
+
+// Inspect returns the details of an image from the JSON printed by the image inspect command.
+func (c cli) Inspect(image string) (*ImageInfo, error) {
+	cmd := exec.Command(c.binary, "image", "inspect", image)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+	return parseInspect(image, output)
+}
+
+// ListContainers returns every container of the engine, including stopped ones.
+func (c cli) ListContainers() ([]ContainerInfo, error) {
+	containers, err := c.listContainers(c.binary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s containers: %w", c.name, err)
+	}
+	return containers, nil
+}
+
+// RemoveContainer removes a stopped container.
+func (c cli) RemoveContainer(id string) error {
+	cmd := exec.Command(c.binary, "container", "rm", id)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to remove container %s: %w: %s", id, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// listDockerContainers lists containers with a docker compatible client, which prints a JSON object per container.
+// Names and labels are printed as comma separated strings.
+func listDockerContainers(binary string) ([]ContainerInfo, error) {
+	output, err := exec.Command(binary, "ps", "--all", "--no-trunc", "--format", "{{json .}}").Output()
+	if err != nil {
+		return nil, err
+	}
+	var containers []ContainerInfo
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry struct {
+			ID     string
+			Names  string
+			Image  string
+			State  string
+			Labels string
+		}
+		err = json.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			return nil, err
+		}
+		name, _, _ := strings.Cut(entry.Names, ",")
+		containers = append(containers, ContainerInfo{
+			ID:     entry.ID,
+			Name:   name,
+			Image:  entry.Image,
+			State:  entry.State,
+			Labels: parseLabels(entry.Labels),
+		})
+	}
+	return containers, nil
+}
+
+// parseLabels parses labels printed as a comma separated list of KEY=VALUE pairs.
+func parseLabels(labels string) map[string]string {
+	parsed := map[string]string{}
+	for _, label := range strings.Split(labels, ",") {
+		if key, value, ok := strings.Cut(label, "="); ok {
+			parsed[key] = value
+		}
+	}
+	return parsed
+}
+
+// parseInspect parses the JSON array printed by the image inspect command.
+func parseInspect(image string, output []byte) (*ImageInfo, error) {
+	var infos []ImageInfo
+	err := json.Unmarshal(output, &infos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the details of image %s: %w", image, err)
+	}
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("image %s not found", image)
+	}
+	return &infos[0], nil
+}
+
+// userArg returns the --user flag for the user of the options, if one is set.
+func userArg(opts RunOptions) []string {
+	if opts.User == "" {
+		return nil
+	}
+	return []string{"--user", opts.User}
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// probeTimeout bounds how long an engine may take to answer the probe before it is considered not running.
+const probeTimeout = 10 * time.Second
+
+// Status describes whether a container engine is installed and usable on this machine.
+type Status struct {
+	Name      string
+	Path      string // Path of the command line client, if installed
+	Installed bool
+	Running   bool  // The daemon or socket of the engine responds
+	Rootless  bool  // The engine runs without root privileges
+	Err       error // Why the engine is not usable
+}
+
+// Usable reports whether the engine is installed and running.
+func (s Status) Usable() bool {
+	return s.Installed && s.Running
+}
+
+// String describes the status for display in prompts. E.g. "podman (running, rootless)"
+func (s Status) String() string {
+	switch {
+	case !s.Installed:
+		return fmt.Sprintf("%s (not installed)", s.Name)
+	case !s.Running:
+		return fmt.Sprintf("%s (not running)", s.Name)
+	case s.Rootless:
+		return fmt.Sprintf("%s (running, rootless)", s.Name)
+	}
+	return fmt.Sprintf("%s (running)", s.Name)
+}
+
+// Check returns an error that explains why the engine cannot be used, or nil when it is usable.
+func (s Status) Check() error {
+	if s.Usable() {
+		return nil
+	}
+	return s.Err
+}
+
+// Detect probes every supported engine, in the order of Names.
+func Detect() []Status {
+	statuses := make([]Status, 0, len(Names))
+	for _, name := range Names {
+		backend, _ := New(name)
+		statuses = append(statuses, backend.Probe())
+	}
+	return statuses
+}
+
+// Check returns an error when the engine with the given name is unknown, not installed or not running.
+func Check(name string) error {
+	backend, err := New(name)
+	if err != nil {
+		return err
+	}
+	return backend.Probe().Check()
+}
+
+// Probe looks the client up in PATH and asks the engine for its info, which fails when the daemon or socket does not
+// respond. The info also tells whether the engine runs rootless.
+func (c cli) Probe() Status {
+	status := Status{Name: c.name}
+	path, err := exec.LookPath(c.binary)
+	if err != nil {
+		status.Err = fmt.Errorf("%s is not installed: %s was not found in PATH", c.name, c.binary)
+		return status
+	}
+	status.Path = path
+	status.Installed = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, "info", "--format", c.rootlessFormat).CombinedOutput()
+	if err != nil {
+		status.Err = fmt.Errorf("%s is installed but not running: %s", c.name, firstLine(output, err))
+		return status
+	}
+	status.Running = true
+	status.Rootless = c.rootless(strings.TrimSpace(string(output)))
+	return status
+}
+
+// firstLine returns the first line of a failed command's output, or the error when there is none.
+func firstLine(output []byte, err error) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	if line == "" {
+		return err.Error()
+	}
+	return line
+}
+
+// securityOptionsFormat prints the security options reported by docker and nerdctl, which include
+// name=rootless for rootless engines.
+const securityOptionsFormat = "{{range .SecurityOptions}}{{println .}}{{end}}"
+
+// rootlessSecurityOption reports whether the security options printed with securityOptionsFormat mark the engine as
+// rootless.
+func rootlessSecurityOption(output string) bool {
+	return strings.Contains(output, "name=rootless")
+}
@@ -0,0 +1,13 @@
+package engine
+
+// NewDocker returns the engine for the docker command line client.
+func NewDocker() Engine {
+	return cli{
+		name:           Docker,
+		binary:         "docker",
+		userArgs:       userArg,
+		rootlessFormat: securityOptionsFormat,
+		rootless:       rootlessSecurityOption,
+		listContainers: listDockerContainers,
+	}
+}
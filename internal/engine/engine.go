@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Names of the supported container engines.
+const (
+	Docker  = "docker"
+	Podman  = "podman"
+	Nerdctl = "nerdctl"
+	// DockerAPI talks to the Docker Engine API instead of running the docker client.
+	DockerAPI = "docker-api"
+	// PodmanAPI talks to the docker compatible API of podman instead of running the podman client.
+	PodmanAPI = "podman-api"
+)
+
+// Names lists the supported container engines in the order they are offered.
+var Names = []string{Docker, Podman, Nerdctl, DockerAPI, PodmanAPI}
+
+// Engine builds, runs and manages container images with a container engine.
+type Engine interface {
+	// Name returns the name of the engine. E.g. docker
+	Name() string
+	// Build builds an image from a Dockerfile.
+	Build(opts BuildOptions) error
+	// Run runs a container and waits for it to exit. The exit code of the container is returned.
+	Run(opts RunOptions) (int, error)
+	// Pull pulls an image from its registry. The progress is written to output, or to os.Stdout when output is nil.
+	Pull(image string, output io.Writer) error
+	// RemoveImage removes an image from the local image store.
+	RemoveImage(image string) error
+	// ImageExists reports whether an image is present in the local image store.
+	ImageExists(image string) bool
+	// Inspect returns the details of an image in the local image store.
+	Inspect(image string) (*ImageInfo, error)
+	// ListContainers returns every container of the engine, including stopped ones.
+	ListContainers() ([]ContainerInfo, error)
+	// RemoveContainer removes a stopped container.
+	RemoveContainer(id string) error
+	// Probe reports whether the engine is installed and running on this machine.
+	Probe() Status
+}
+
+// CommandLine is implemented by engines that run a command line client. It returns the arguments passed to the client,
+// which are shown to the user and used in tests.
+type CommandLine interface {
+	BuildArgs(opts BuildOptions) []string
+	RunArgs(opts RunOptions) []string
+}
+
+// BuildOptions describes an image build.
+type BuildOptions struct {
+	Dockerfile string
+	Context    string
+	Tags       []string
+	BuildArgs  []string // Build arguments in KEY=VALUE form
+	Target     string
+	Labels     []string // Image labels in KEY=VALUE form
+	Platform   string
+	Output     io.Writer // Receives the build output as it is produced. Defaults to os.Stdout
+}
+
+// RunOptions describes a container run.
+type RunOptions struct {
+	Image   string
+	Command []string
+	Env     []string // Environment variables in KEY=VALUE form, or NAME to pass the host value through
+	Volumes []string // Volume mappings in HOST:CONTAINER[:MODE] form
+	Workdir string
+	// EnvFiles are files of environment variables, one KEY=VALUE or NAME per line. Env takes precedence over them.
+	EnvFiles []string
+	// Name is the name of the container. Empty lets the engine generate one.
+	Name   string
+	Labels []string // Container labels in KEY=VALUE form
+	// Remove removes the container when it exits.
+	Remove bool
+	// Interactive keeps stdin open so input can be typed or piped into the container.
+	Interactive bool
+	// TTY allocates a pseudo terminal for the container.
+	TTY bool
+	// User runs the container as the given UID:GID.
+	User string
+	// KeepUserID maps the host user to the same UID and GID in the container. Only rootless podman supports it; the
+	// other engines ignore it.
+	KeepUserID bool
+	Stdin      io.Reader // Defaults to os.Stdin
+	Stdout     io.Writer // Defaults to os.Stdout
+	Stderr     io.Writer // Defaults to os.Stderr
+}
+
+// ContainerInfo describes a container of the engine.
+type ContainerInfo struct {
+	ID     string
+	Name   string
+	Image  string // Image reference the container was created from
+	State  string // E.g. running or exited
+	Labels map[string]string
+}
+
+// Running reports whether the container is still running.
+func (c ContainerInfo) Running() bool {
+	return c.State == "running" || c.State == "paused"
+}
+
+// ImageInfo holds the details of an image in the local image store.
+type ImageInfo struct {
+	ID          string   `json:"Id"`
+	RepoDigests []string `json:"RepoDigests"`
+}
+
+// buildOutput returns the writer that receives the output of a build.
+func buildOutput(opts BuildOptions) io.Writer {
+	if opts.Output == nil {
+		return os.Stdout
+	}
+	return opts.Output
+}
+
+// New returns the engine with the given name.
+func New(name string) (Engine, error) {
+	switch name {
+	case Docker:
+		return NewDocker(), nil
+	case Podman:
+		return NewPodman(), nil
+	case Nerdctl:
+		return NewNerdctl(), nil
+	case DockerAPI:
+		return NewDockerAPI(), nil
+	case PodmanAPI:
+		return NewPodmanAPI(), nil
+	}
+	return nil, fmt.Errorf("invalid container engine %q: must be one of %s", name, strings.Join(Names, ", "))
+}
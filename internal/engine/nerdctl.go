@@ -0,0 +1,13 @@
+package engine
+
+// NewNerdctl returns the engine for the nerdctl command line client of containerd.
+func NewNerdctl() Engine {
+	return cli{
+		name:           Nerdctl,
+		binary:         "nerdctl",
+		userArgs:       userArg,
+		rootlessFormat: securityOptionsFormat,
+		rootless:       rootlessSecurityOption,
+		listContainers: listDockerContainers,
+	}
+}
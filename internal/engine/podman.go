@@ -0,0 +1,35 @@
+package engine
+
+import "os/exec"
+
+// NewPodman returns the engine for the podman command line client.
+func NewPodman() Engine {
+	return cli{
+		name:           Podman,
+		binary:         "podman",
+		userArgs:       podmanUserArgs,
+		rootlessFormat: "{{.Host.Security.Rootless}}",
+		rootless: func(output string) bool {
+			return output == "true"
+		},
+		listContainers: listPodmanContainers,
+	}
+}
+
+// listPodmanContainers lists containers with podman, which prints them as a JSON array with proper lists of names
+// and maps of labels.
+func listPodmanContainers(binary string) ([]ContainerInfo, error) {
+	output, err := exec.Command(binary, "ps", "--all", "--format", "json").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseContainerList(output)
+}
+
+// podmanUserArgs maps the host user with --userns=keep-id, which only podman supports, and falls back to --user.
+func podmanUserArgs(opts RunOptions) []string {
+	if opts.KeepUserID {
+		return []string{"--userns=keep-id"}
+	}
+	return userArg(opts)
+}
@@ -1,8 +1,12 @@
 package gitter
 
 import (
+	"bytes"
+	"fmt"
 	"net/url"
 	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/hashicorp/go-getter"
 )
@@ -11,20 +15,22 @@ import (
 type Gitter struct {
 	Name        string
 	Url         *url.URL
+	Ref         string // Branch, tag or commit to check out. Empty follows the remote default branch
 	Destination string
 	_client     getter.GitGetter
 }
 
 // NewGitter creates and returns a new Gitter instance initialized with the provided name, Git repository URL,
-// and destination path.
-func NewGitter(name, gitUrl, destination string) *Gitter {
-	thisUrl, err := url.Parse(gitUrl)
+// ref and destination path. The git:: prefix that forces a git source is stripped from the URL.
+func NewGitter(name, gitUrl, ref, destination string) *Gitter {
+	thisUrl, err := url.Parse(strings.TrimPrefix(gitUrl, "git::"))
 	if err != nil {
 		panic(err)
 	}
 	return &Gitter{
 		Name:        name,
 		Url:         thisUrl,
+		Ref:         ref,
 		Destination: destination,
 		_client:     getter.GitGetter{},
 	}
@@ -37,9 +43,114 @@ func (g *Gitter) Clone() error {
 	if err != nil {
 		println(err)
 	}
-	err = g._client.Get(g.Destination, g.Url)
+	err = g._client.Get(g.Destination, g.refUrl())
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// Update fetches the remote into the existing checkout in the destination directory and fast-forwards the
+// checked out branch. When Ref names a branch, that branch is checked out and fast-forwarded. When Ref names a tag
+// or a commit, the checkout stays at that ref and nothing is fetched unless the ref changed. The working tree is
+// left untouched if the fetch or the fast-forward fails.
+func (g *Gitter) Update() error {
+	if g.Ref != "" && !g.isBranch() {
+		pinned, err := g.git("rev-parse", "--verify", "--quiet", g.Ref+"^{commit}")
+		head, _ := g.Commit()
+		if err == nil && pinned == head {
+			return nil
+		}
+	}
+	if _, err := g.git("fetch", "--tags", "origin"); err != nil {
+		return err
+	}
+	switch {
+	case g.Ref == "":
+		defaultBranch, err := g.git("rev-parse", "--abbrev-ref", "origin/HEAD")
+		if err != nil {
+			return err
+		}
+		defaultBranch = strings.TrimPrefix(defaultBranch, "origin/")
+		// The checkout was pinned to another ref before, go back to the remote default branch
+		if branch, err := g.git("symbolic-ref", "--quiet", "--short", "HEAD"); err != nil || branch != defaultBranch {
+			if _, err = g.git("checkout", defaultBranch); err != nil {
+				return err
+			}
+		}
+		if _, err := g.git("merge", "--ff-only", "@{upstream}"); err != nil {
+			return err
+		}
+	case g.isBranch():
+		if _, err := g.git("checkout", g.Ref); err != nil {
+			return err
+		}
+		if _, err := g.git("merge", "--ff-only", "origin/"+g.Ref); err != nil {
+			return err
+		}
+	default:
+		if _, err := g.git("checkout", "--detach", g.Ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit returns the commit SHA currently checked out in the destination directory.
+func (g *Gitter) Commit() (string, error) {
+	return g.git("rev-parse", "HEAD")
+}
+
+// Head returns the branch checked out in the destination directory, or the commit SHA if HEAD is detached.
+func (g *Gitter) Head() (string, error) {
+	if branch, err := g.git("symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		return branch, nil
+	}
+	return g.Commit()
+}
+
+// Reset moves the checkout in the destination directory back to the given head, as returned by Head, and the given
+// commit. A branch is checked out again and reset to the commit, a detached head is checked out at the commit.
+func (g *Gitter) Reset(head, commit string) error {
+	if head == commit {
+		_, err := g.git("checkout", "--force", "--detach", commit)
+		return err
+	}
+	if _, err := g.git("checkout", "--force", head); err != nil {
+		return err
+	}
+	_, err := g.git("reset", "--hard", commit)
+	return err
+}
+
+// isBranch reports whether Ref names a branch of the origin remote.
+func (g *Gitter) isBranch() bool {
+	_, err := g.git("show-ref", "--verify", "--quiet", "refs/remotes/origin/"+g.Ref)
+	return err == nil
+}
+
+// refUrl returns the repository URL with the ref query parameter understood by go-getter.
+func (g *Gitter) refUrl() *url.URL {
+	if g.Ref == "" {
+		return g.Url
+	}
+	refUrl := *g.Url
+	query := refUrl.Query()
+	query.Set("ref", g.Ref)
+	refUrl.RawQuery = query.Encode()
+	return &refUrl
+}
+
+// git runs a git command inside the destination directory and returns its trimmed standard output.
+func (g *Gitter) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.Destination
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
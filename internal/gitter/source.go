@@ -0,0 +1,180 @@
+package gitter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+	"gitlab.com/locke-codes/container-cli/internal/config"
+)
+
+// forcedGetterRegexp matches the go-getter syntax that forces a getter for a source. E.g. s3::https://...
+var forcedGetterRegexp = regexp.MustCompile(`^([A-Za-z0-9]+)::(.+)$`)
+
+// archiveExtensions lists the archive formats that are downloaded and unpacked instead of cloned.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz", ".tar", ".zip"}
+
+// DetectSource returns the kind of source of the given project URL. Archives served over https are downloaded,
+// s3:: and gcs:: sources are fetched from object storage, file:// sources are copied, and everything else served over
+// https or ssh is cloned with git. Archives are only downloaded over plain http when the URL carries a go-getter
+// checksum, which go-getter verifies before the archive is unpacked.
+func DetectSource(src string) (string, error) {
+	if ms := forcedGetterRegexp.FindStringSubmatch(src); ms != nil {
+		switch ms[1] {
+		case "git":
+			return config.SourceGit, nil
+		case "s3":
+			return config.SourceS3, nil
+		case "gcs":
+			return config.SourceGCS, nil
+		case "http", "https":
+			if err := checkPlainHTTP(ms[2]); err != nil {
+				return "", err
+			}
+			return config.SourceHTTP, nil
+		case "file":
+			return config.SourceFile, nil
+		default:
+			return "", fmt.Errorf("unsupported source type: %s", ms[1])
+		}
+	}
+	parsedURL, err := url.Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL format: %w", err)
+	}
+	switch parsedURL.Scheme {
+	case "file":
+		return config.SourceFile, nil
+	case "https", "http":
+		if err = checkPlainHTTP(src); err != nil {
+			return "", err
+		}
+		if isArchive(parsedURL) {
+			return config.SourceHTTP, nil
+		}
+		if parsedURL.Scheme == "http" {
+			return "", fmt.Errorf("invalid URL scheme: http is only allowed for archives")
+		}
+		return config.SourceGit, nil
+	case "ssh":
+		return config.SourceGit, nil
+	default:
+		return "", fmt.Errorf("invalid URL scheme: %s (only https, ssh, file, s3:: and gcs:: are allowed)", parsedURL.Scheme)
+	}
+}
+
+// checkPlainHTTP returns an error for a plain http URL without a go-getter checksum, since nothing else guarantees
+// that the downloaded source was not tampered with.
+func checkPlainHTTP(src string) error {
+	parsedURL, err := url.Parse(src)
+	if err != nil {
+		return fmt.Errorf("invalid URL format: %w", err)
+	}
+	if parsedURL.Scheme == "http" && parsedURL.Query().Get("checksum") == "" {
+		return fmt.Errorf("invalid URL scheme: http is only allowed for archives with a checksum, e.g. ?checksum=sha256:...")
+	}
+	return nil
+}
+
+// isArchive reports whether the URL points to an archive by its extension or go-getter's archive query parameter.
+func isArchive(parsedURL *url.URL) bool {
+	if parsedURL.Query().Get("archive") != "" {
+		return true
+	}
+	for _, extension := range archiveExtensions {
+		if strings.HasSuffix(strings.ToLower(parsedURL.Path), extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// Downloader retrieves project sources that are not git repositories, such as archives and object storage, into a
+// destination directory using the go-getter getter that matches the source type.
+type Downloader struct {
+	Name        string
+	Source      string
+	Url         string
+	Destination string
+}
+
+// NewDownloader creates and returns a new Downloader for the given source type, URL and destination path.
+func NewDownloader(name, source, srcUrl, destination string) *Downloader {
+	return &Downloader{
+		Name:        name,
+		Source:      source,
+		Url:         srcUrl,
+		Destination: destination,
+	}
+}
+
+// Download fetches the source into a staging directory and then moves it into the destination. A previous download
+// is kept next to the destination until Discard or Restore is called, so a failed download leaves it untouched.
+func (d *Downloader) Download() error {
+	getters, err := d.getters()
+	if err != nil {
+		return err
+	}
+	staging := d.Destination + ".download"
+	_ = os.RemoveAll(staging)
+	client := &getter.Client{
+		Ctx:     context.Background(),
+		Src:     d.Url,
+		Dst:     staging,
+		Mode:    getter.ClientModeDir,
+		Getters: getters,
+	}
+	if err = client.Get(); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+	_ = os.RemoveAll(d.previous())
+	if _, err = os.Stat(d.Destination); err == nil {
+		if err = os.Rename(d.Destination, d.previous()); err != nil {
+			return err
+		}
+	}
+	return os.Rename(staging, d.Destination)
+}
+
+// Restore puts the previous download back into the destination.
+func (d *Downloader) Restore() error {
+	if _, err := os.Stat(d.previous()); err != nil {
+		return nil
+	}
+	if err := os.RemoveAll(d.Destination); err != nil {
+		return err
+	}
+	return os.Rename(d.previous(), d.Destination)
+}
+
+// Discard removes the previous download.
+func (d *Downloader) Discard() error {
+	return os.RemoveAll(d.previous())
+}
+
+// previous returns the path where the previous download is kept.
+func (d *Downloader) previous() string {
+	return d.Destination + ".previous"
+}
+
+// getters returns the go-getter getters for the source type of the downloader.
+func (d *Downloader) getters() (map[string]getter.Getter, error) {
+	switch d.Source {
+	case config.SourceHTTP:
+		httpGetter := &getter.HttpGetter{Netrc: true}
+		return map[string]getter.Getter{"http": httpGetter, "https": httpGetter}, nil
+	case config.SourceS3:
+		return map[string]getter.Getter{"s3": new(getter.S3Getter)}, nil
+	case config.SourceGCS:
+		return map[string]getter.Getter{"gcs": new(getter.GCSGetter)}, nil
+	case config.SourceFile:
+		return map[string]getter.Getter{"file": &getter.FileGetter{Copy: true}}, nil
+	default:
+		return nil, fmt.Errorf("source type %s cannot be downloaded", d.Source)
+	}
+}
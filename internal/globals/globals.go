@@ -11,6 +11,19 @@ var HomeDir string
 // DefaultContainerCliConfigPath represents the default path for the container CLI configuration file.
 var DefaultContainerCliConfigPath string
 
+// LocalBinDirectory is the directory where the ccli binary and the project command scripts are installed.
+var LocalBinDirectory string
+
+// LogDirectory is the directory where the build logs of projects are kept, in a subdirectory per project.
+var LogDirectory string
+
+// HomesDirectory is the directory where the isolated home directories of projects are kept, in a subdirectory per
+// project.
+var HomesDirectory string
+
+// Version is the version of the running ccli binary. It is set by main from the build flags.
+var Version string
+
 // ProjectId is a constant representing the unique identifier for the project in the container CLI configuration.
 const ProjectId string = "47137983"
 
@@ -20,8 +33,15 @@ const UserHomeContainer = "/opt/usr/home"
 // ContextDirectoryContainer defines the directory path in the container where the runtime context is mounted.
 const ContextDirectoryContainer = "/opt/context"
 
-// init initializes the HomeDir and DefaultContainerCliConfigPath variables with appropriate default values.
+// ImageHistoryLength is the number of previously installed commits whose images are kept for rollback.
+const ImageHistoryLength = 5
+
+// init initializes the HomeDir, DefaultContainerCliConfigPath, LocalBinDirectory, LogDirectory and HomesDirectory
+// variables with appropriate default values.
 func init() {
 	HomeDir, _ = os.UserHomeDir()
 	DefaultContainerCliConfigPath = filepath.Join(HomeDir, ".config/container-cli/config.yaml")
+	LocalBinDirectory = filepath.Join(HomeDir, ".local/bin")
+	LogDirectory = filepath.Join(HomeDir, ".local/state/container-cli/logs")
+	HomesDirectory = filepath.Join(HomeDir, ".local/share/container-cli/homes")
 }
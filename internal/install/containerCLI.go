@@ -1,14 +1,17 @@
 package install
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/manifoldco/promptui"
 	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/engine"
 	"gitlab.com/locke-codes/container-cli/internal/globals"
 	"gitlab.com/locke-codes/container-cli/internal/utils"
 	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
@@ -53,7 +56,7 @@ func ContainerCLIInstall(engine string, force bool) error {
 	}
 	engine, err = promptEngine(engine)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		return err
 	}
 	fmt.Printf("Installing container-cli for %s\n", engine)
 	return ContainerCLI(engine)
@@ -91,6 +94,10 @@ func ContainerCLI(engine string) error {
 	configFile := config.NewContainerCliConfig(engine)
 	err = configFile.LoadConfig()
 	if !utils.FileExists(globals.DefaultContainerCliConfigPath) {
+		_, err = probeEngine(configFile, engine)
+		if err != nil {
+			return err
+		}
 		err = configFile.SaveConfig()
 		if err != nil {
 			return err
@@ -100,35 +107,107 @@ func ContainerCLI(engine string) error {
 	return nil
 }
 
-func promptEngine(engine string) (string, error) {
-	validate := func(engineName string) error {
-		if engineName != "" && engineName != "docker" && engineName != "podman" {
-			return fmt.Errorf("invalid container engine: must be 'docker' or 'podman'")
-		}
-		// Path is valid
+// ValidateEngine checks that the given container engine is supported. An empty engine is accepted.
+func ValidateEngine(engineName string) error {
+	if engineName == "" {
 		return nil
 	}
+	_, err := engine.New(engineName)
+	return err
+}
 
-	if engine == "" {
-		engines := []string{
-			"docker",
-			"podman",
-		}
-		prompt := promptui.Select{
-			Label: "Select a container engine",
-			Items: engines,
-		}
+// CheckEngine checks that the given container engine is supported, installed and running. An empty engine is accepted.
+func CheckEngine(engineName string) error {
+	if engineName == "" {
+		return nil
+	}
+	return engine.Check(engineName)
+}
 
-		_, result, err := prompt.Run()
+// probeEngine checks that the given container engine is supported, installed and running, and records in the
+// configuration whether it runs rootless. Commands read the record to map the user, so they do not probe the engine
+// on every run. It reports whether the record changed.
+func probeEngine(configFile *config.ContainerCliConfig, engineName string) (bool, error) {
+	backend, err := engine.New(engineName)
+	if err != nil {
+		return false, err
+	}
+	status := backend.Probe()
+	err = status.Check()
+	if err != nil {
+		return false, err
+	}
+	return configFile.SetRootless(engineName, status.Rootless), nil
+}
+
+// SetEngine changes the global container engine in the configuration file and relinks the commands of every project,
+// so that projects without their own engine run with the new one.
+func SetEngine(engineName string) error {
+	if engineName == "" {
+		return fmt.Errorf("container engine is required")
+	}
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	_, err = probeEngine(configFile, engineName)
+	if err != nil {
+		return err
+	}
+	configFile.ContainerEngine = engineName
+	err = configFile.SaveConfig()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, projectConfig := range configFile.Projects {
+		err := NewProjectFromConfig(&projectConfig).InstallScript()
 		if err != nil {
-			return "", fmt.Errorf("Prompt failed %v\n", err)
+			errs = append(errs, fmt.Errorf("project %s: %w", projectConfig.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// promptEngine lets the user pick a container engine when none is given. Engines that are installed and running are
+// detected first, and the selection starts on the first of them. A given engine must be installed and running.
+func promptEngine(engineName string) (string, error) {
+	if engineName != "" {
+		fmt.Printf("Using container engine: %s\n", engineName)
+		return engineName, engine.Check(engineName)
+	}
+
+	statuses := engine.Detect()
+	cursor := -1
+	for i, status := range statuses {
+		if status.Usable() {
+			cursor = i
+			break
 		}
-		engine = result
-	} else {
-		fmt.Printf("Using container engine: %s\n", engine)
-		return engine, validate(engine)
 	}
+	if cursor == -1 {
+		var reasons []string
+		for _, status := range statuses {
+			reasons = append(reasons, status.Err.Error())
+		}
+		return "", fmt.Errorf("no usable container engine found:\n  %s", strings.Join(reasons, "\n  "))
+	}
+	prompt := promptui.Select{
+		Label:     "Select a container engine",
+		Items:     statuses,
+		CursorPos: cursor,
+	}
+
+	i, _, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("Prompt failed %v\n", err)
+	}
+	err = statuses[i].Check()
+	if err != nil {
+		return "", err
+	}
+	engineName = statuses[i].Name
 
-	fmt.Printf("Using container engine: %s\n", engine)
-	return engine, nil
+	fmt.Printf("Using container engine: %s\n", engineName)
+	return engineName, nil
 }
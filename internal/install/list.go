@@ -0,0 +1,95 @@
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/container"
+	"gitlab.com/locke-codes/container-cli/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectStatus describes an installed project together with the state of its scripts and container image.
+type ProjectStatus struct {
+	Name           string   `json:"name" yaml:"name"`
+	Alias          string   `json:"alias" yaml:"alias"`
+	Aliases        []string `json:"aliases" yaml:"aliases"`
+	Path           string   `json:"path" yaml:"path"`
+	DefaultCommand string   `json:"defaultCommand" yaml:"defaultCommand"`
+	ImageName      string   `json:"imageName" yaml:"imageName"`
+	ScriptExists   bool     `json:"scriptExists" yaml:"scriptExists"`
+	ImageExists    bool     `json:"imageExists" yaml:"imageExists"`
+}
+
+// ProjectList prints every project in the container CLI configuration in the requested output format.
+// Supported formats are "table", "json" and "yaml".
+func ProjectList(output string) error {
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	statuses := make([]ProjectStatus, 0, len(configFile.Projects))
+	for _, projectConfig := range configFile.Projects {
+		statuses = append(statuses, NewProjectStatus(&projectConfig))
+	}
+	return WriteProjectList(os.Stdout, statuses, output)
+}
+
+// NewProjectStatus builds the ProjectStatus for the given project configuration by checking the script and image.
+func NewProjectStatus(projectConfig *config.ProjectConfig) ProjectStatus {
+	project := NewProjectFromConfig(projectConfig)
+	// A project whose engine is unknown is listed without its image
+	containerObj, err := container.NewContainer(projectConfig)
+	imageExists := err == nil && containerObj.ImageExists()
+	var aliases []string
+	scriptExists := true
+	for _, command := range project.AllCommands() {
+		aliases = append(aliases, command.Alias)
+		scriptExists = scriptExists && utils.FileExists(scriptPath(command.Alias))
+	}
+	return ProjectStatus{
+		Name:           projectConfig.Name,
+		Alias:          project.Alias(),
+		Aliases:        aliases,
+		Path:           projectConfig.Path,
+		DefaultCommand: projectConfig.DefaultCommand,
+		ImageName:      containerObj.ImageName,
+		ScriptExists:   scriptExists,
+		ImageExists:    imageExists,
+	}
+}
+
+// WriteProjectList writes the project statuses to w as a table, JSON or YAML.
+func WriteProjectList(w io.Writer, statuses []ProjectStatus, output string) error {
+	switch output {
+	case "", "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "NAME\tALIASES\tPATH\tCOMMAND\tIMAGE\tSCRIPT\tIMAGE EXISTS")
+		for _, status := range statuses {
+			aliases := status.Alias
+			if len(status.Aliases) > 0 {
+				aliases = strings.Join(status.Aliases, ",")
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\t%t\n",
+				status.Name, aliases, status.Path, status.DefaultCommand, status.ImageName,
+				status.ScriptExists, status.ImageExists)
+		}
+		return tw.Flush()
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(statuses)
+	case "yaml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(statuses)
+	default:
+		return fmt.Errorf("invalid output format: %s (must be table, json or yaml)", output)
+	}
+}
@@ -0,0 +1,200 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/utils"
+)
+
+// InstallLock records the resolved commit, image and Dockerfile hash of the installed project in the lock file.
+func (p *Project) InstallLock() error {
+	lockFile, err := config.LoadLockFile(config.DefaultLockFilePath())
+	if err != nil {
+		return err
+	}
+	projectLock, err := p.lockEntry()
+	if err != nil {
+		return err
+	}
+	lockFile.SetProject(projectLock)
+	fmt.Printf("Locking %s in %s\n", p.Name, lockFile.Path)
+	return lockFile.Save()
+}
+
+// RemoveLock removes the project from the lock file.
+func (p *Project) RemoveLock() error {
+	lockFile, err := config.LoadLockFile(config.DefaultLockFilePath())
+	if err != nil {
+		return err
+	}
+	if lockFile.GetProject(p.Name) == nil {
+		return nil
+	}
+	fmt.Printf("Removing lock for %s\n", p.Name)
+	lockFile.RemoveProject(p.Name)
+	return lockFile.Save()
+}
+
+// lockEntry builds the lock entry of the project from its checkout, its Dockerfile and its image.
+func (p *Project) lockEntry() (config.ProjectLock, error) {
+	containerObj, err := p.container()
+	if err != nil {
+		return config.ProjectLock{}, err
+	}
+	projectLock := config.ProjectLock{
+		Name:        p.Name,
+		Commit:      p.Commit(),
+		CcliVersion: globals.Version,
+	}
+	projectLock.ImageID, err = containerObj.ImageID()
+	if err != nil {
+		return projectLock, err
+	}
+	projectLock.ImageDigest, err = containerObj.ImageDigest()
+	if err != nil {
+		return projectLock, err
+	}
+	if !containerObj.Prebuilt {
+		projectLock.DockerfileHash, err = utils.FileSHA256(containerObj.Dockerfile)
+		if err != nil {
+			return projectLock, err
+		}
+	}
+	return projectLock, nil
+}
+
+// lockedProject returns the lock entry of the project, or an error if the project is not in the lock file.
+func (p *Project) lockedProject() (*config.ProjectLock, error) {
+	lockFile, err := config.LoadLockFile(config.DefaultLockFilePath())
+	if err != nil {
+		return nil, err
+	}
+	projectLock := lockFile.GetProject(p.Name)
+	if projectLock == nil {
+		return nil, fmt.Errorf("project %s is not in the lock file %s", p.Name, lockFile.Path)
+	}
+	return projectLock, nil
+}
+
+// fetchLocked fetches the project source and verifies it against the lock file. Cloned and downloaded sources are
+// fetched into a staging directory next to the project path, which only replaces the installed source once it
+// matches, so a refused install leaves the working install untouched.
+func (p *Project) fetchLocked() error {
+	if p.Source == config.SourceImage || p.Source == config.SourceLocal {
+		err := p.Fetch()
+		if err != nil {
+			return err
+		}
+		return p.verifyLockedSource(p.Path())
+	}
+	staging := p.Path() + ".locked"
+	err := os.RemoveAll(staging)
+	if err != nil {
+		return fmt.Errorf("error removing %s: %w", staging, err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+	err = p.fetchInto(staging)
+	if err != nil {
+		return err
+	}
+	err = p.verifyLockedSource(staging)
+	if err != nil {
+		return err
+	}
+	err = p.RemoveSource()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(staging, p.Path())
+	if err != nil {
+		return fmt.Errorf("error moving %s to %s: %w", staging, p.Path(), err)
+	}
+	return nil
+}
+
+// verifyLockedSource returns an error if the commit or the Dockerfile of the source in the given directory do not
+// match the lock file.
+func (p *Project) verifyLockedSource(directory string) error {
+	projectLock, err := p.lockedProject()
+	if err != nil {
+		return err
+	}
+	if commit := p.commitAt(directory); projectLock.Commit != "" && commit != projectLock.Commit {
+		return fmt.Errorf("commit %s of %s does not match the locked commit %s", commit, p.Name, projectLock.Commit)
+	}
+	// Prebuilt images are locked without a Dockerfile hash
+	if projectLock.DockerfileHash == "" {
+		return nil
+	}
+	manifest, err := config.LoadManifest(directory)
+	if err != nil {
+		return err
+	}
+	dockerfile := manifest.DockerfilePath(directory)
+	dockerfileHash, err := utils.FileSHA256(dockerfile)
+	if err != nil {
+		return err
+	}
+	if dockerfileHash != projectLock.DockerfileHash {
+		return fmt.Errorf("dockerfile %s does not match the locked hash %s", dockerfile, projectLock.DockerfileHash)
+	}
+	return nil
+}
+
+// pinLockedImage makes a prebuilt project pull its image by the locked digest. The pull then cannot fetch a different
+// image, and does not move the tag of the installed image.
+func (p *Project) pinLockedImage() error {
+	projectLock, err := p.lockedProject()
+	if err != nil {
+		return err
+	}
+	containerObj, err := p.container()
+	if err != nil {
+		return err
+	}
+	if !containerObj.Prebuilt || projectLock.ImageDigest == "" {
+		return nil
+	}
+	if imageRepository(containerObj.Image()) != imageRepository(projectLock.ImageDigest) {
+		return fmt.Errorf("image %s does not match the locked image %s", containerObj.Image(), projectLock.ImageDigest)
+	}
+	p.Image = projectLock.ImageDigest
+	return nil
+}
+
+// imageRepository returns the repository of an image reference without its tag or digest.
+func imageRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// verifyLockedImage returns an error if a pulled prebuilt image does not match the locked digest. Locally built
+// images are not compared, because their IDs differ between machines.
+func (p *Project) verifyLockedImage() error {
+	projectLock, err := p.lockedProject()
+	if err != nil {
+		return err
+	}
+	containerObj, err := p.container()
+	if err != nil {
+		return err
+	}
+	if !containerObj.Prebuilt || projectLock.ImageDigest == "" {
+		return nil
+	}
+	digest, err := containerObj.ImageDigest()
+	if err != nil {
+		return err
+	}
+	if digest != projectLock.ImageDigest {
+		return fmt.Errorf("image %s has digest %s but %s is locked", containerObj.Image(), digest, projectLock.ImageDigest)
+	}
+	return nil
+}
@@ -1,11 +1,15 @@
 package install
 
 import (
+	"errors"
 	"fmt"
-	"net/url"
+	"io"
 	"os"
 	"path"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/manifoldco/promptui"
 	"gitlab.com/locke-codes/container-cli/internal/config"
@@ -17,11 +21,24 @@ import (
 
 // Project represents a project with a name, URL, destination directory, and a default command.
 type Project struct {
-	Name                 string // Name of the project
-	URL                  string // Repository URL
-	DestinationDirectory string // Destination for the repository
-	CommandAlias         string // CommandAlias. This will be the cli command. This defaults to Name
-	DefaultCommand       string // Command to execute in the container
+	Name                 string                 // Name of the project
+	URL                  string                 // Repository URL
+	Ref                  string                 // Branch, tag or commit to install. Defaults to the remote default branch
+	Source               string                 // Kind of source the project is installed from. E.g. git or local
+	LocalPath            string                 // Directory of a local project that is used in place instead of being cloned
+	DestinationDirectory string                 // Destination for the repository
+	CommandAlias         string                 // CommandAlias. This will be the cli command. This defaults to Name
+	DefaultCommand       string                 // Command to execute in the container
+	BuildArgs            []string               // Build arguments in KEY=VALUE form. Values may reference ${VAR} from the host
+	Target               string                 // Stage of a multi-stage Dockerfile to build
+	Labels               []string               // Image labels in KEY=VALUE form
+	Platform             string                 // Platform of the image to build. E.g. linux/amd64
+	Image                string                 // Prebuilt image reference to pull instead of building. E.g. registry/name:tag
+	Locked               bool                   // Refuse to install anything that does not match the lock file
+	Commands             []config.CommandConfig // Additional commands of the image, each installed under its own alias
+	Engine               string                 // Container engine of the project. Empty uses the global engine
+	Quiet                bool                   // Only write the build output to the build log, not to the terminal
+	previousAliases      []string               // Aliases linked before this install or update
 }
 
 // Alias returns the CommandAlias of the project if set; otherwise, it defaults to the project's Name.
@@ -33,104 +50,605 @@ func (p *Project) Alias() string {
 }
 
 // Path constructs and returns the full path of the project by combining the destination directory and project name.
+// Local projects are used in place, so their path is the local directory. Projects that only use a prebuilt image
+// have no source and return an empty path.
 func (p *Project) Path() string {
+	switch p.Source {
+	case config.SourceLocal:
+		return p.LocalPath
+	case config.SourceImage:
+		return ""
+	}
 	return path.Join(p.DestinationDirectory, p.Name)
 }
 
+// ScriptPath returns the location of the executable script that runs the project's default command.
+func (p *Project) ScriptPath() string {
+	return scriptPath(p.Alias())
+}
+
+// scriptPath returns the location of the command link for the given command alias.
+func scriptPath(alias string) string {
+	return path.Join(globals.LocalBinDirectory, alias)
+}
+
+// AllCommands returns every command of the project with its alias. The default command comes first, followed by the
+// commands declared in the project manifest and the project configuration. A later command replaces an earlier one
+// with the same alias.
+func (p *Project) AllCommands() []config.CommandConfig {
+	manifest, err := config.LoadManifest(p.Path())
+	if err != nil {
+		manifest = &config.Manifest{}
+	}
+	projectConfig := p.ProjectConfig()
+	defaultCommand := config.CommandConfig{Alias: p.Alias(), Command: p.DefaultCommand}
+	return mergeCommands(defaultCommand, manifest.Commands, projectConfig.Commands)
+}
+
+// Fetch retrieves the project source into the project path. Git projects are cloned, archives and object storage
+// sources are downloaded, and local projects are used in place and only checked. Projects that only use a prebuilt
+// image have nothing to fetch.
+func (p *Project) Fetch() error {
+	if p.Source == config.SourceImage {
+		return nil
+	}
+	if p.Source == config.SourceLocal {
+		if !utils.DirExists(p.Path()) {
+			return fmt.Errorf("local project directory %s not found", p.Path())
+		}
+		fmt.Printf("Using local directory %s\n", p.Path())
+		return nil
+	}
+	if p.downloaded() {
+		return p.fetchInto(p.Path())
+	}
+	err := p.RemoveSource()
+	if err != nil {
+		return err
+	}
+	return p.Clone()
+}
+
+// fetchInto downloads or clones the project source into the given directory.
+func (p *Project) fetchInto(directory string) error {
+	if p.downloaded() {
+		fmt.Printf("Downloading %s\n", p.URL)
+		client := gitter.NewDownloader(p.Name, p.Source, p.URL, directory)
+		err := client.Download()
+		if err != nil {
+			return err
+		}
+		return client.Discard()
+	}
+	fmt.Printf("Cloning %s\n", p.URL)
+	return gitter.NewGitter(p.Name, p.URL, p.Ref, directory).Clone()
+}
+
 // Clone clones the project repository from the specified URL into the designated destination directory.
 func (p *Project) Clone() error {
-	fmt.Printf("Cloning %s\n", p.URL)
-	client := gitter.NewGitter(p.Name, p.URL, p.Path())
-	err := client.Clone()
+	return p.fetchInto(p.Path())
+}
+
+// Commit returns the commit SHA checked out in the project directory, or an empty string if it cannot be determined.
+func (p *Project) Commit() string {
+	return p.commitAt(p.Path())
+}
+
+// commitAt returns the commit SHA checked out in the given directory. Sources that are not git checkouts have none.
+func (p *Project) commitAt(directory string) string {
+	if p.downloaded() || p.Source == config.SourceImage {
+		return ""
+	}
+	commit, err := gitter.NewGitter(p.Name, p.URL, p.Ref, directory).Commit()
 	if err != nil {
-		return err
+		return ""
 	}
-	return nil
+	return commit
 }
 
-// Install executes the installation process for the project, including cloning, configuring, and setting up scripts.
-func (p *Project) Install() error {
-	fmt.Printf("Installing %s\n", p.Name)
-	err := p.Uninstall()
+// downloaded reports whether the project source is downloaded with go-getter instead of cloned or used in place.
+func (p *Project) downloaded() bool {
+	switch p.Source {
+	case config.SourceHTTP, config.SourceS3, config.SourceGCS, config.SourceFile:
+		return true
+	}
+	return false
+}
+
+// Update updates the project from the same kind of source it was installed from. Prebuilt images are pulled again,
+// local projects are rebuilt from their directory, downloaded projects are downloaded again, and git projects are
+// fetched and fast-forwarded.
+func (p *Project) Update() error {
+	fmt.Printf("Updating %s\n", p.Name)
+	p.previousAliases = p.linkedAliases()
+	switch {
+	case p.Source == config.SourceImage:
+		err := p.build()
+		if err != nil {
+			return err
+		}
+		return p.activate()
+	case p.Source == config.SourceLocal:
+		fmt.Printf("Rebuilding %s from %s\n", p.Name, p.Path())
+		err := p.build()
+		if err != nil {
+			return err
+		}
+		return p.activate()
+	case p.downloaded():
+		return p.updateDownload()
+	default:
+		return p.updateGit()
+	}
+}
+
+// updateDownload downloads the project source again and rebuilds the container image. If the download or the build
+// fails, the previous source is restored so that the installed image, script and configuration keep working.
+func (p *Project) updateDownload() error {
+	fmt.Printf("Downloading %s\n", p.URL)
+	client := gitter.NewDownloader(p.Name, p.Source, p.URL, p.Path())
+	err := client.Download()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		return fmt.Errorf("error downloading %s: %w", p.Name, err)
 	}
-	err = p.Clone()
+	err = p.build()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		fmt.Printf("Build failed, restoring the previous source of %s\n", p.Name)
+		if restoreErr := client.Restore(); restoreErr != nil {
+			return errors.Join(err, restoreErr)
+		}
+		return err
 	}
-	err = p.InstallConfig()
+	err = client.Discard()
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 	}
+	return p.activate()
+}
+
+// updateGit fetches the latest changes into the existing checkout and fast-forwards it. The container image is only
+// rebuilt when the checked out commit changed or the image is missing. If the build fails, the checkout is moved
+// back to the previous branch or detached commit so that the installed image, script and configuration keep working.
+func (p *Project) updateGit() error {
+	client := gitter.NewGitter(p.Name, p.URL, p.Ref, p.Path())
+	previousHead, err := client.Head()
+	if err != nil {
+		return fmt.Errorf("error reading current head of %s: %w", p.Name, err)
+	}
+	previousCommit, err := client.Commit()
+	if err != nil {
+		return fmt.Errorf("error reading current commit of %s: %w", p.Name, err)
+	}
+	err = client.Update()
+	if err != nil {
+		return fmt.Errorf("error fetching %s: %w", p.Name, err)
+	}
+	currentCommit, err := client.Commit()
+	if err != nil {
+		return fmt.Errorf("error reading updated commit of %s: %w", p.Name, err)
+	}
+	if currentCommit == previousCommit && p.imageExists() {
+		fmt.Printf("%s is already up to date at %s\n", p.Name, currentCommit)
+		return p.saveRef()
+	}
+	fmt.Printf("Updating %s from %s to %s\n", p.Name, previousCommit, currentCommit)
+	err = p.build()
+	if err != nil {
+		fmt.Printf("Build failed, resetting %s to %s\n", p.Name, previousCommit)
+		if resetErr := client.Reset(previousHead, previousCommit); resetErr != nil {
+			return errors.Join(err, resetErr)
+		}
+		return err
+	}
+	return p.activate()
+}
+
+// build applies the project manifest and builds the container image from the current source, or pulls the prebuilt
+// image if the project uses one.
+func (p *Project) build() error {
+	err := p.applyManifest()
+	if err != nil {
+		return err
+	}
+	return p.BuildContainer()
+}
+
+// activate records the project in the configuration file and the lock file, and installs its script.
+func (p *Project) activate() error {
+	err := p.InstallConfig()
+	if err != nil {
+		return err
+	}
+	err = p.recordEngine()
+	if err != nil {
+		return err
+	}
 	err = p.InstallScript()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		return err
 	}
-	err = p.BuildContainer()
+	return p.InstallLock()
+}
+
+// checkEngine returns an error when the container engine of the project, or the global engine if the project has
+// none, is not set or unknown, so an install fails before anything is fetched.
+func (p *Project) checkEngine() error {
+	engineName := p.ProjectConfig().Engine
+	if engineName == "" {
+		var err error
+		engineName, err = config.GetContainerEngineFromConfig()
+		if err != nil {
+			return fmt.Errorf("error getting container engine: %w", err)
+		}
+	}
+	return ValidateEngine(engineName)
+}
+
+// recordEngine probes the container engine of the project, or the global engine if the project has none, and records
+// in the configuration file whether it runs rootless.
+func (p *Project) recordEngine() error {
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	engineName := p.ProjectConfig().Engine
+	if engineName == "" {
+		engineName = configFile.ContainerEngine
+	}
+	changed, err := probeEngine(configFile, engineName)
+	if err != nil || !changed {
+		return err
+	}
+	return configFile.SaveConfig()
+}
+
+// imageExists reports whether the project's container image is present for the configured container engine.
+func (p *Project) imageExists() bool {
+	containerObj, err := p.container()
+	return err == nil && containerObj.ImageExists()
+}
+
+// container returns the Container used to manage the project's image.
+func (p *Project) container() (container.Container, error) {
+	projectConfig := p.ProjectConfig()
+	return container.NewContainer(&projectConfig)
+}
+
+// ProjectConfig returns the configuration entry for the project. Settings of an existing entry with the same name are
+// kept, while the source, build paths and commands are taken from the project and its manifest.
+func (p *Project) ProjectConfig() config.ProjectConfig {
+	var projectConfig config.ProjectConfig
+	if configFile, err := config.LoadConfig(); err == nil {
+		if existingProject := configFile.GetProject(p.Name); existingProject != nil {
+			projectConfig = *existingProject
+		}
+	}
+	manifest, err := config.LoadManifest(p.Path())
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
+		manifest = &config.Manifest{}
+	}
+	projectConfig.Name = p.Name
+	projectConfig.URL = p.URL
+	projectConfig.Ref = p.Ref
+	projectConfig.Source = p.Source
+	projectConfig.Path = p.Path()
+	projectConfig.Commit = p.Commit()
+	projectConfig.Dockerfile = manifest.DockerfilePath(p.Path())
+	projectConfig.BuildDirectory = p.Path()
+	projectConfig.BuildContext = manifest.ContextPath(p.Path())
+	projectConfig.DefaultCommand = p.DefaultCommand
+	projectConfig.CommandAlias = p.CommandAlias
+	// Build settings of an existing entry are kept unless they were given again
+	if len(p.BuildArgs) > 0 {
+		projectConfig.BuildArgs = p.BuildArgs
+	}
+	if p.Target != "" {
+		projectConfig.Target = p.Target
+	}
+	if len(p.Labels) > 0 {
+		projectConfig.Labels = p.Labels
+	}
+	if p.Platform != "" {
+		projectConfig.Platform = p.Platform
+	}
+	if p.Image != "" {
+		projectConfig.Image = p.Image
+	}
+	if len(p.Commands) > 0 {
+		projectConfig.Commands = p.Commands
+	}
+	if p.Engine != "" {
+		projectConfig.Engine = p.Engine
+	}
+	return projectConfig
+}
+
+// applyManifest reads the manifest of the checked out project and checks that it supports this version of ccli.
+// The manifest's command and alias are used where no flag set them, and the command is prompted for if neither did.
+func (p *Project) applyManifest() error {
+	manifest, err := config.LoadManifest(p.Path())
+	if err != nil {
+		return err
+	}
+	err = manifest.CheckVersion(globals.Version)
+	if err != nil {
+		return err
+	}
+	if p.DefaultCommand == "" {
+		p.DefaultCommand = manifest.Command
+	}
+	if p.CommandAlias == "" {
+		p.CommandAlias = manifest.Alias
+	}
+	for _, command := range p.AllCommands() {
+		err = ValidateName(command.Alias)
+		if err != nil {
+			return err
+		}
+		err = p.checkAlias(command.Alias)
+		if err != nil {
+			return err
+		}
 	}
+	p.DefaultCommand, err = promptCommand(p.DefaultCommand)
 	return err
 }
 
-// BuildContainer Build the project dockerfile
+// Install executes the installation process for the project, including cloning, configuring, and setting up scripts.
+// Locked installs verify the source and the image against the lock file before they replace the installed ones.
+func (p *Project) Install() error {
+	fmt.Printf("Installing %s\n", p.Name)
+	err := p.checkEngine()
+	if err != nil {
+		return err
+	}
+	p.previousAliases = p.linkedAliases()
+	if p.Locked {
+		err = p.fetchLocked()
+	} else {
+		err = p.Fetch()
+	}
+	if err != nil {
+		return err
+	}
+	err = p.applyManifest()
+	if err != nil {
+		return err
+	}
+	if p.Locked {
+		err = p.pinLockedImage()
+		if err != nil {
+			return err
+		}
+	}
+	err = p.BuildContainer()
+	if err != nil {
+		return err
+	}
+	if p.Locked {
+		err = p.verifyLockedImage()
+		if err != nil {
+			return err
+		}
+	}
+	return p.activate()
+}
+
+// BuildContainer Build the project dockerfile, or pull the image if the project uses a prebuilt image. The output of
+// either is written to a new build log.
 func (p *Project) BuildContainer() error {
-	projectConfig := config.ProjectConfig{
-		Name:           p.Name,
-		Path:           p.Path(),
-		Dockerfile:     path.Join(p.Path(), "Dockerfile"),
-		BuildDirectory: p.Path(),
-		BuildContext:   p.Path(),
-		DefaultCommand: p.DefaultCommand,
+	containerObj, err := p.container()
+	if err != nil {
+		return err
+	}
+	logFile, err := p.createBuildLog()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logFile.Close() }()
+	var output io.Writer = logFile
+	if !p.Quiet {
+		output = io.MultiWriter(logFile, container.NewPrefixWriter(os.Stdout, fmt.Sprintf("[%s] ", p.Name)))
+	}
+	if containerObj.Prebuilt {
+		err = containerObj.Pull(output)
+		if err != nil {
+			return fmt.Errorf("error pulling image: %v. The pull log is at %s", err, logFile.Name())
+		}
+		fmt.Printf("Pull log written to %s\n", logFile.Name())
+		return nil
 	}
-	containerObj := container.NewContainer(&projectConfig)
-	err := containerObj.Build()
+	err = containerObj.Build(output)
 	if err != nil {
-		return fmt.Errorf("error building container: %v", err)
+		return fmt.Errorf("error building container: %v. The build log is at %s", err, logFile.Name())
 	}
+	fmt.Printf("Build log written to %s\n", logFile.Name())
 	return nil
 }
 
-// InstallScript creates and installs an executable script for the project in the user's local bin directory.
+// createBuildLog creates the log file for a build of the project, named after the time the build starts. A build
+// that starts in the same second as an earlier one gets a numbered log instead of replacing the earlier log.
+func (p *Project) createBuildLog() (*os.File, error) {
+	dir := filepath.Join(globals.LogDirectory, p.Name)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating log directory %s: %w", dir, err)
+	}
+	started := time.Now().Format("20060102-150405")
+	name := started + ".log"
+	for i := 2; ; i++ {
+		logFile, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if !os.IsExist(err) {
+			return logFile, err
+		}
+		name = fmt.Sprintf("%s-%d.log", started, i)
+	}
+}
+
+// InstallScript links every command of the project into the user's local bin directory. Each link points at the ccli
+// binary, which runs the command in the project's image when invoked under the alias.
 func (p *Project) InstallScript() error {
-	projectConfig := config.ProjectConfig{
-		Name:           p.Name,
-		Path:           p.Path(),
-		Dockerfile:     path.Join(p.Path(), "Dockerfile"),
-		BuildDirectory: p.Path(),
-		BuildContext:   p.Path(),
-		DefaultCommand: p.DefaultCommand,
-	}
-	containerObj := container.NewContainer(&projectConfig)
-	runCmd := containerObj.GetRunCommand()
-	commandStr := fmt.Sprintf("podman %s", strings.Join(runCmd, " "))
-	// File contents
-	fileContent := fmt.Sprintf(`#!/usr/bin/env bash
-%s $*`, commandStr)
-
-	filePath := path.Join(globals.HomeDir, ".local/bin", p.Alias())
-	// Write the file content
-	err = os.WriteFile(filePath, []byte(fileContent), 0755)
-	if err != nil {
-		fmt.Println("Error writing file:", err)
-		return err
+	target, err := binaryPath()
+	if err != nil {
+		return fmt.Errorf("failed to find the ccli binary: %w", err)
 	}
+	var aliases []string
+	for _, command := range p.AllCommands() {
+		err := installCommandLink(command.Alias, target)
+		if err != nil {
+			return err
+		}
+		aliases = append(aliases, command.Alias)
+	}
+	// Commands that were dropped from the manifest or the configuration are no longer provided by the project
+	for _, alias := range p.previousAliases {
+		if slices.Contains(aliases, alias) {
+			continue
+		}
+		filePath := scriptPath(alias)
+		fmt.Printf("Removing command link %s\n", filePath)
+		err := os.Remove(filePath)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing %s: %w", filePath, err)
+		}
+	}
+	return nil
+}
 
-	// Make the file executable
-	err = os.Chmod(filePath, 0755)
+// linkedAliases returns the aliases of the installed project, or nil if the project is not installed yet.
+func (p *Project) linkedAliases() []string {
+	configFile, err := config.LoadConfig()
 	if err != nil {
-		fmt.Println("Error setting executable permissions:", err)
-		return err
+		return nil
+	}
+	projectConfig := configFile.GetProject(p.Name)
+	if projectConfig == nil {
+		return nil
+	}
+	var aliases []string
+	for _, command := range projectCommands(projectConfig) {
+		aliases = append(aliases, command.Alias)
+	}
+	return aliases
+}
+
+// checkAlias returns an error if the alias cannot be linked for the project: it is a name of the ccli binary, a
+// command of another installed project, or a file in the local bin directory that ccli did not install.
+func (p *Project) checkAlias(alias string) error {
+	if slices.Contains(binaryNames, alias) {
+		return fmt.Errorf("alias %s is reserved for the ccli binary", alias)
+	}
+	if configFile, err := config.LoadConfig(); err == nil {
+		for i := range configFile.Projects {
+			other := &configFile.Projects[i]
+			if other.Name == p.Name {
+				continue
+			}
+			for _, command := range projectCommands(other) {
+				if command.Alias == alias {
+					return fmt.Errorf("alias %s is already used by project %s", alias, other.Name)
+				}
+			}
+		}
+	}
+	filePath := scriptPath(alias)
+	if _, err := os.Lstat(filePath); os.IsNotExist(err) || slices.Contains(p.previousAliases, alias) {
+		return nil
+	}
+	if linksToBinary(filePath) {
+		return nil
+	}
+	return fmt.Errorf("alias %s cannot be linked: %s already exists and was not installed by ccli", alias, filePath)
+}
+
+// linksToBinary reports whether the file is a link to the ccli binary.
+func linksToBinary(filePath string) bool {
+	info, err := os.Lstat(filePath)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+	target, err := binaryPath()
+	if err != nil {
+		return false
+	}
+	linked, err := os.Stat(filePath)
+	if err != nil {
+		return false
 	}
+	binary, err := os.Stat(target)
+	return err == nil && os.SameFile(linked, binary)
+}
 
-	fmt.Printf("Script created and made executable at: %s\n", filePath)
+// installCommandLink creates the link for a single command alias, replacing a script or link left by an earlier install.
+func installCommandLink(alias, target string) error {
+	filePath := scriptPath(alias)
+	err := os.Remove(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing %s: %w", filePath, err)
+	}
+	err = os.Symlink(target, filePath)
+	if err != nil {
+		return fmt.Errorf("error linking %s to %s: %w", filePath, target, err)
+	}
+	fmt.Printf("Command linked at: %s\n", filePath)
 	return nil
 }
 
 // InstallConfig installs or updates the project configuration in the container CLI configuration file.
+// A freshly installed project runs its latest image again, and its commit is added to the history used for rollbacks.
+// Locally built images of commits that drop out of the history are removed.
 func (p *Project) InstallConfig() error {
 	fmt.Printf("Installing config for %s\n", p.Name)
+	projectConfig := p.ProjectConfig()
+	projectConfig.ImageTag = ""
+	var droppedCommits []string
+	projectConfig.History, droppedCommits = recordHistory(projectConfig.History, projectConfig.Commit)
+	err := p.saveProjectConfig(projectConfig)
+	if err != nil {
+		return err
+	}
+	if len(droppedCommits) == 0 {
+		return nil
+	}
+	containerObj, err := p.container()
+	if err != nil {
+		return err
+	}
+	// Prebuilt images are not tagged per commit, so there is nothing to remove
+	if containerObj.Prebuilt {
+		return nil
+	}
+	for _, commit := range droppedCommits {
+		containerObj.ImageTag = container.ShortCommit(commit)
+		if containerObj.ImageExists() {
+			fmt.Printf("Removing image %s\n", containerObj.Image())
+			if err = containerObj.RemoveImage(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		}
+	}
+	return nil
+}
+
+// saveRef records the ref of the project in its entry of the container CLI configuration file, e.g. when the project is
+// pinned to a ref that is already checked out. The rest of the entry is kept as it is.
+func (p *Project) saveRef() error {
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	projectConfig := configFile.GetProject(p.Name)
+	if projectConfig == nil || projectConfig.Ref == p.Ref {
+		return nil
+	}
+	projectConfig.Ref = p.Ref
+	return p.saveProjectConfig(*projectConfig)
+}
+
+// saveProjectConfig adds the project configuration to the container CLI configuration file, or replaces the existing
+// entry of the project.
+func (p *Project) saveProjectConfig(projectConfig config.ProjectConfig) error {
 	engine, err := config.GetContainerEngineFromConfig()
 	if err != nil {
 		return fmt.Errorf("error getting container engine: %w", err)
@@ -141,15 +659,6 @@ func (p *Project) InstallConfig() error {
 		return err
 	}
 	existingProject := configFile.GetProject(p.Name)
-	projectConfig := config.ProjectConfig{
-		Name:           p.Name,
-		Path:           p.Path(),
-		Dockerfile:     path.Join(p.Path(), "Dockerfile"),
-		BuildDirectory: p.Path(),
-		BuildContext:   p.Path(),
-		DefaultCommand: p.DefaultCommand,
-		CommandAlias:   p.CommandAlias,
-	}
 	if existingProject == nil {
 		configFile.Projects = append(configFile.Projects, projectConfig)
 	} else {
@@ -167,17 +676,137 @@ func (p *Project) InstallConfig() error {
 	return nil
 }
 
-// Uninstall removes all files and directories related to the project at the constructed project path.
-// TODO: Also remove any symlinks and scripts
-func (p *Project) Uninstall() error {
+// Uninstall removes the project's script, configuration and lock entries, container image and source directory.
+// The image and the source directory are kept when keepImage or keepSource are set. Every step is attempted and
+// reported, and the errors of the failed steps are returned together.
+func (p *Project) Uninstall(keepImage, keepSource bool) error {
+	fmt.Printf("Uninstalling %s\n", p.Name)
+	var errs []error
+	if err := p.RemoveScript(); err != nil {
+		errs = append(errs, err)
+	}
+	if keepImage {
+		fmt.Printf("Keeping image for %s\n", p.Name)
+	} else if err := p.RemoveImage(); err != nil {
+		errs = append(errs, err)
+	}
+	if keepSource || p.Source == config.SourceLocal {
+		fmt.Printf("Keeping source at %s\n", p.Path())
+	} else if err := p.RemoveSource(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := p.RemoveConfig(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := p.RemoveLock(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// RemoveSource removes all files and directories related to the project at the constructed project path.
+func (p *Project) RemoveSource() error {
+	if p.Path() == "" {
+		return nil
+	}
 	fmt.Printf("Removing %s\n", p.Path())
 	err := os.RemoveAll(p.Path())
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		return fmt.Errorf("error removing source %s: %w", p.Path(), err)
 	}
 	return nil
 }
 
+// RemoveScript removes the executable scripts of all project commands from the user's local bin directory.
+func (p *Project) RemoveScript() error {
+	var errs []error
+	for _, command := range p.AllCommands() {
+		filePath := scriptPath(command.Alias)
+		fmt.Printf("Removing script %s\n", filePath)
+		err := os.Remove(filePath)
+		if err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("error removing script %s: %w", filePath, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RemoveImage removes the project's container image using the configured container engine.
+// Locally built images are removed together with the tags of the commits kept for rollbacks.
+func (p *Project) RemoveImage() error {
+	projectConfig := p.ProjectConfig()
+	containerObj, err := container.NewContainer(&projectConfig)
+	if err != nil {
+		return err
+	}
+	tags := []string{"latest"}
+	if !containerObj.Prebuilt {
+		for _, commit := range projectConfig.History {
+			tags = append(tags, container.ShortCommit(commit))
+		}
+	}
+	var errs []error
+	for _, tag := range tags {
+		containerObj.ImageTag = tag
+		fmt.Printf("Removing image %s\n", containerObj.Image())
+		if !containerObj.ImageExists() {
+			fmt.Printf("Image %s not found\n", containerObj.Image())
+			continue
+		}
+		if err := containerObj.RemoveImage(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RemoveConfig removes the project from the container CLI configuration file.
+func (p *Project) RemoveConfig() error {
+	fmt.Printf("Removing config for %s\n", p.Name)
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	err = configFile.RemoveProjectByName(p.Name)
+	if err != nil {
+		return err
+	}
+	return configFile.SaveConfig()
+}
+
+// ProjectUpdate updates the project with the given name as recorded in the container CLI configuration.
+// A non-empty ref replaces the ref the project is pinned to.
+func ProjectUpdate(name, ref string, quiet bool) error {
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	projectConfig := configFile.GetProject(name)
+	if projectConfig == nil {
+		return fmt.Errorf("project with name %s not found", name)
+	}
+	project := NewProjectFromConfig(projectConfig)
+	project.Quiet = quiet
+	if ref != "" {
+		fmt.Printf("Pinning %s to %s\n", name, ref)
+		project.Ref = ref
+	}
+	return project.Update()
+}
+
+// ProjectUninstall uninstalls the project with the given name as recorded in the container CLI configuration.
+func ProjectUninstall(name string, keepImage, keepSource bool) error {
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	projectConfig := configFile.GetProject(name)
+	if projectConfig == nil {
+		return fmt.Errorf("project with name %s not found", name)
+	}
+	return NewProjectFromConfig(projectConfig).Uninstall(keepImage, keepSource)
+}
+
 // ValidateName checks if the provided name contains any invalid characters and returns an error if it does.
 func ValidateName(name string) error {
 	chars := "!@#*+$&%\\/=~ \t\n"
@@ -246,19 +875,9 @@ func promptCommand(command string) (string, error) {
 func promptUrl(projectUrl string) (string, error) {
 	var err error
 	validate := func(input string) error {
-		// Parse the input string as a URL
-		parsedURL, err := url.Parse(input)
-		if err != nil {
-			return fmt.Errorf("invalid URL format: %w", err)
-		}
-
-		// Ensure the URL scheme is "https"
-		if parsedURL.Scheme != "https" && parsedURL.Scheme != "ssh" {
-			return fmt.Errorf("invalid URL scheme: %s (only https and ssh is allowed)", parsedURL.Scheme)
-		}
-
-		// No errors, the URL is valid
-		return nil
+		// Ensure the URL is a git repository, an archive or an object storage source
+		_, err := gitter.DetectSource(input)
+		return err
 	}
 	if projectUrl == "" {
 		prompt := promptui.Prompt{
@@ -333,6 +952,22 @@ func promptDestination(dest string) (string, error) {
 	return dest, nil
 }
 
+// NewProjectFromConfig creates a Project instance from a project entry of the container CLI configuration.
+func NewProjectFromConfig(projectConfig *config.ProjectConfig) *Project {
+	return &Project{
+		Name:                 projectConfig.Name,
+		URL:                  projectConfig.URL,
+		Ref:                  projectConfig.Ref,
+		Source:               projectConfig.Source,
+		LocalPath:            projectConfig.Path,
+		DestinationDirectory: path.Dir(projectConfig.Path),
+		DefaultCommand:       projectConfig.DefaultCommand,
+		CommandAlias:         projectConfig.CommandAlias,
+		Commands:             projectConfig.Commands,
+		Engine:               projectConfig.Engine,
+	}
+}
+
 // NewProject creates a new Project instance by prompting for missing or invalid inputs and initializing its fields.
 func NewProject(args map[string]string) *Project {
 	name := args["name"]
@@ -340,23 +975,45 @@ func NewProject(args map[string]string) *Project {
 	dest := args["dest"]
 	command := args["command"]
 	alias := args["alias"]
+	ref := args["ref"]
+	localPath := args["path"]
+	image := args["image"]
 	var err error
 	name, err = promptName(name)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 	}
-	if alias == "" {
-		alias = name
+	// The command and alias may also come from the project manifest. They are validated and prompted for once the
+	// project has been cloned.
+	if image != "" && projectUrl == "" && localPath == "" {
+		return &Project{
+			Name:           name,
+			Source:         config.SourceImage,
+			Image:          image,
+			DefaultCommand: command,
+			CommandAlias:   alias,
+		}
 	}
-	err = ValidateName(alias)
-	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+	if localPath != "" {
+		localPath, err = localProjectPath(localPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return &Project{
+			Name:                 name,
+			Source:               config.SourceLocal,
+			LocalPath:            localPath,
+			DestinationDirectory: path.Dir(localPath),
+			DefaultCommand:       command,
+			CommandAlias:         alias,
+			Image:                image,
+		}
 	}
-	command, err = promptCommand(command)
+	projectUrl, err = promptUrl(projectUrl)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 	}
-	projectUrl, err = promptUrl(projectUrl)
+	source, err := gitter.DetectSource(projectUrl)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 	}
@@ -367,8 +1024,43 @@ func NewProject(args map[string]string) *Project {
 	return &Project{
 		Name:                 name,
 		URL:                  projectUrl,
+		Ref:                  ref,
+		Source:               source,
 		DestinationDirectory: dest,
 		DefaultCommand:       command,
 		CommandAlias:         alias,
+		Image:                image,
+	}
+}
+
+// ParseCommands parses additional project commands given in ALIAS=COMMAND form. E.g. tflint=tflint
+func ParseCommands(values []string) ([]config.CommandConfig, error) {
+	var commands []config.CommandConfig
+	for _, value := range values {
+		alias, command, found := strings.Cut(value, "=")
+		if !found || alias == "" || command == "" {
+			return nil, fmt.Errorf("invalid command %s: must be in ALIAS=COMMAND form", value)
+		}
+		if err := ValidateName(alias); err != nil {
+			return nil, err
+		}
+		commands = append(commands, config.CommandConfig{Alias: alias, Command: command})
+	}
+	return commands, nil
+}
+
+// localProjectPath expands and resolves the directory of a local project to an absolute path.
+func localProjectPath(localPath string) (string, error) {
+	expanded, err := utils.ExpandPath(localPath)
+	if err != nil {
+		return "", err
+	}
+	absolute, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", localPath, err)
+	}
+	if !utils.DirExists(absolute) {
+		return "", fmt.Errorf("local project directory %s not found", absolute)
 	}
+	return absolute, nil
 }
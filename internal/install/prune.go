@@ -0,0 +1,115 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/container"
+	"gitlab.com/locke-codes/container-cli/internal/engine"
+)
+
+// Prune removes the stopped containers left behind by project commands, for every engine used by a project. With
+// dryRun set, the containers are only listed.
+func Prune(dryRun bool) error {
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	// Group the projects and the images the old wrapper scripts ran by the engine that runs them
+	engines := map[string]engine.Engine{}
+	projects := map[string][]string{}
+	images := map[string][]string{}
+	var errs []error
+	for _, projectConfig := range configFile.Projects {
+		containerObj, err := container.NewContainer(&projectConfig)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		name := containerObj.Engine.Name()
+		engines[name] = containerObj.Engine
+		projects[name] = append(projects[name], projectConfig.Name)
+		if !containerObj.Prebuilt {
+			images[name] = append(images[name], legacyImage(projectConfig.Name))
+		}
+	}
+
+	removed := 0
+	for name, backend := range engines {
+		containers, err := backend.ListContainers()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, leftover := range LeftoverContainers(containers, projects[name], images[name]) {
+			if dryRun {
+				fmt.Printf("Would remove container %s (%s) of %s\n", leftover.Name, leftover.Image, name)
+				continue
+			}
+			fmt.Printf("Removing container %s (%s) of %s\n", leftover.Name, leftover.Image, name)
+			err := backend.RemoveContainer(leftover.ID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			removed++
+		}
+	}
+	if !dryRun {
+		fmt.Printf("Removed %d containers\n", removed)
+	}
+	return errors.Join(errs...)
+}
+
+// legacyImage returns the image that the wrapper scripts of older versions of ccli ran for a locally built project:
+// the project name with the default latest tag.
+func legacyImage(project string) string {
+	return project + ":latest"
+}
+
+// LeftoverContainers returns the stopped containers that were run by ccli for the given projects. They carry the
+// project label or the ccli-<project>- name prefix, or, for containers of the old wrapper scripts that did neither,
+// were created from one of the given legacy images. Containers of prebuilt images such as postgres:16 are only
+// matched by label or name, so containers the user ran from the same image are kept.
+func LeftoverContainers(containers []engine.ContainerInfo, projects []string, legacyImages []string) []engine.ContainerInfo {
+	images := map[string]bool{}
+	for _, image := range legacyImages {
+		images[normalizeImage(image)] = true
+	}
+	var leftovers []engine.ContainerInfo
+	for _, c := range containers {
+		if c.Running() {
+			continue
+		}
+		_, labelled := c.Labels[container.ProjectLabel]
+		if labelled || hasProjectName(c.Name, projects) || images[normalizeImage(c.Image)] {
+			leftovers = append(leftovers, c)
+		}
+	}
+	return leftovers
+}
+
+// hasProjectName reports whether a container name is one generated by ccli for one of the projects.
+func hasProjectName(name string, projects []string) bool {
+	for _, project := range projects {
+		if strings.HasPrefix(name, fmt.Sprintf("ccli-%s-", project)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImage removes the default registry prefixes that engines add to local and Docker Hub images and adds the
+// default latest tag to untagged references, so image references compare equal across engines.
+func normalizeImage(image string) string {
+	for _, prefix := range []string{"localhost/", "docker.io/library/", "docker.io/"} {
+		image = strings.TrimPrefix(image, prefix)
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if !strings.ContainsAny(name, ":@") {
+		image += ":latest"
+	}
+	return image
+}
@@ -0,0 +1,108 @@
+package install
+
+import (
+	"fmt"
+	"strings"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/container"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+)
+
+// ProjectRollback points the script of the project with the given name at the image of an earlier commit.
+// Without a commit, the project is rolled back to the commit installed before the one it currently runs.
+func ProjectRollback(name, commit string) error {
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	projectConfig := configFile.GetProject(name)
+	if projectConfig == nil {
+		return fmt.Errorf("project with name %s not found", name)
+	}
+	return NewProjectFromConfig(projectConfig).Rollback(commit)
+}
+
+// Rollback points the project's script at the image tagged with an earlier commit from the project history, without
+// rebuilding anything. The image of that commit must still exist.
+func (p *Project) Rollback(commit string) error {
+	projectConfig := p.ProjectConfig()
+	containerObj, err := container.NewContainer(&projectConfig)
+	if err != nil {
+		return err
+	}
+	if containerObj.Prebuilt {
+		return fmt.Errorf("project %s uses the prebuilt image %s and cannot be rolled back", p.Name, containerObj.ImageName)
+	}
+	target, err := RollbackTarget(projectConfig.History, projectConfig.ImageTag, commit)
+	if err != nil {
+		return err
+	}
+	containerObj.ImageTag = container.ShortCommit(target)
+	if !containerObj.ImageExists() {
+		return fmt.Errorf("image %s not found", containerObj.Image())
+	}
+	fmt.Printf("Rolling back %s to %s\n", p.Name, containerObj.Image())
+	projectConfig.ImageTag = containerObj.ImageTag
+	err = p.saveProjectConfig(projectConfig)
+	if err != nil {
+		return err
+	}
+	return p.InstallScript()
+}
+
+// RollbackTarget returns the commit from the history to roll back to. The history is ordered from the most recently
+// installed commit. When commit is empty, the commit installed before the one the current tag points to is returned;
+// otherwise commit may be any unique prefix of a commit in the history.
+func RollbackTarget(history []string, currentTag, commit string) (string, error) {
+	if commit != "" {
+		var matches []string
+		for _, entry := range history {
+			if strings.HasPrefix(entry, commit) {
+				matches = append(matches, entry)
+			}
+		}
+		if len(matches) == 0 {
+			return "", fmt.Errorf("commit %s is not in the project history", commit)
+		}
+		if len(matches) > 1 {
+			return "", fmt.Errorf("commit %s is ambiguous", commit)
+		}
+		return matches[0], nil
+	}
+	current := 0
+	if currentTag != "" && currentTag != "latest" {
+		current = -1
+		for i, entry := range history {
+			if strings.HasPrefix(entry, currentTag) {
+				current = i
+				break
+			}
+		}
+		if current < 0 {
+			return "", fmt.Errorf("current image tag %s is not in the project history", currentTag)
+		}
+	}
+	if current+1 >= len(history) {
+		return "", fmt.Errorf("no earlier commit in the project history")
+	}
+	return history[current+1], nil
+}
+
+// recordHistory adds the commit to the front of the history and limits the history to globals.ImageHistoryLength
+// entries. It returns the new history and the commits that were dropped from it.
+func recordHistory(history []string, commit string) ([]string, []string) {
+	if commit == "" {
+		return history, nil
+	}
+	updated := []string{commit}
+	for _, entry := range history {
+		if entry != commit {
+			updated = append(updated, entry)
+		}
+	}
+	if len(updated) <= globals.ImageHistoryLength {
+		return updated, nil
+	}
+	return updated[:globals.ImageHistoryLength], updated[globals.ImageHistoryLength:]
+}
@@ -0,0 +1,109 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/container"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+)
+
+// binaryNames are the names the ccli binary itself is installed under. They are never treated as project commands.
+var binaryNames = []string{"ccli", "container-cli"}
+
+// CommandAlias returns the project command alias the binary was invoked as, derived from the given os.Args[0], or an
+// empty string when the binary was invoked as ccli itself.
+func CommandAlias(arg0 string) string {
+	alias := strings.TrimSuffix(filepath.Base(arg0), ".exe")
+	for _, name := range binaryNames {
+		if alias == name {
+			return ""
+		}
+	}
+	return alias
+}
+
+// ErrUnknownCommand is returned by RunCommand when no installed project provides the command, e.g. because the ccli
+// binary was renamed instead of invoked through a command link.
+var ErrUnknownCommand = errors.New("no installed project provides the command")
+
+// FindCommand returns the configuration of the project that provides the command alias, together with the command.
+func FindCommand(configFile *config.ContainerCliConfig, alias string) (*config.ProjectConfig, *config.CommandConfig) {
+	for i := range configFile.Projects {
+		projectConfig := &configFile.Projects[i]
+		for _, command := range projectCommands(projectConfig) {
+			if command.Alias == alias {
+				return projectConfig, &command
+			}
+		}
+	}
+	return nil, nil
+}
+
+// projectCommands returns every command of an installed project from its configuration entry and its manifest. Unlike
+// Project.AllCommands it reads nothing else, so resolving a command on every call of a project command stays cheap.
+func projectCommands(projectConfig *config.ProjectConfig) []config.CommandConfig {
+	alias := projectConfig.CommandAlias
+	if alias == "" {
+		alias = projectConfig.Name
+	}
+	manifest, err := config.LoadManifest(projectConfig.Path)
+	if err != nil {
+		manifest = &config.Manifest{}
+	}
+	defaultCommand := config.CommandConfig{Alias: alias, Command: projectConfig.DefaultCommand}
+	return mergeCommands(defaultCommand, manifest.Commands, projectConfig.Commands)
+}
+
+// mergeCommands returns the default command followed by the given lists of commands. A later command replaces an
+// earlier one with the same alias.
+func mergeCommands(defaultCommand config.CommandConfig, lists ...[]config.CommandConfig) []config.CommandConfig {
+	commands := []config.CommandConfig{defaultCommand}
+	for _, command := range slices.Concat(lists...) {
+		i := slices.IndexFunc(commands, func(existing config.CommandConfig) bool {
+			return existing.Alias == command.Alias
+		})
+		if i >= 0 {
+			commands[i] = command
+		} else {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// RunCommand runs the project command installed under alias with the given arguments. The run command is built from
+// the current configuration, so changes to the config file take effect on the next call. The container is attached to
+// the standard streams of ccli and its exit code is returned. ErrUnknownCommand is returned when no installed project
+// provides the command.
+func RunCommand(alias string, args []string) (int, error) {
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		return 1, fmt.Errorf("%w %s: %w", ErrUnknownCommand, alias, err)
+	}
+	projectConfig, command := FindCommand(configFile, alias)
+	if projectConfig == nil {
+		return 1, fmt.Errorf("%w %s", ErrUnknownCommand, alias)
+	}
+	containerObj, err := container.NewContainer(projectConfig)
+	if err != nil {
+		return 1, err
+	}
+	containerObj.DefaultCommand = command.Command
+	return containerObj.Run(args)
+}
+
+// binaryPath returns the path project commands link to. The stable ccli link in the local bin directory is preferred
+// over the running executable, which may live in a versioned directory that is replaced on update.
+func binaryPath() (string, error) {
+	linkPath := filepath.Join(globals.LocalBinDirectory, "ccli")
+	if _, err := os.Stat(linkPath); err == nil {
+		return linkPath, nil
+	}
+	return os.Executable()
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -73,6 +75,25 @@ func FileExists(path string) bool {
 	return !info.IsDir()
 }
 
+// DirExists checks if a directory exists at the given path
+func DirExists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.IsDir()
+}
+
+// FileSHA256 returns the hex encoded SHA-256 hash of the file contents at the given path
+func FileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // CopySlice Helper function to copy a slice of any type
 func CopySlice[T any](original []T) []T {
 	// Create a new slice with the same length as the original
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+)
+
+func TestRemoveProjectByName(t *testing.T) {
+	type testCase struct {
+		name     string
+		remove   string
+		expected []string
+		hasError bool
+	}
+
+	tests := []testCase{
+		{name: "First", remove: "one", expected: []string{"two", "three"}},
+		{name: "Middle", remove: "two", expected: []string{"one", "three"}},
+		{name: "Last", remove: "three", expected: []string{"one", "two"}},
+		{name: "Missing", remove: "four", expected: []string{"one", "two", "three"}, hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configFile := config.ContainerCliConfig{
+				ContainerEngine: "podman",
+				Path:            filepath.Join(t.TempDir(), "config.yaml"),
+				Projects: []config.ProjectConfig{
+					{Name: "one"},
+					{Name: "two"},
+					{Name: "three"},
+				},
+			}
+			err := configFile.RemoveProjectByName(test.remove)
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+			if err = configFile.SaveConfig(); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			loaded := config.ContainerCliConfig{Path: configFile.Path}
+			if err = loaded.LoadConfig(); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			var names []string
+			for _, project := range loaded.Projects {
+				names = append(names, project.Name)
+			}
+			if !reflect.DeepEqual(names, test.expected) {
+				t.Errorf("Output mismatch. Expected %+v but got %+v", test.expected, names)
+			}
+		})
+	}
+}
+
+func TestSaveConfigNestedSettings(t *testing.T) {
+	project := config.ProjectConfig{
+		Name:           "big-salad",
+		URL:            "https://gitlab.com/locke-codes/big-salad.git",
+		Ref:            "v1.2.0",
+		Source:         config.SourceGit,
+		Commit:         "0123456789abcdef0123456789abcdef01234567",
+		BuildArgs:      []string{"MIRROR=https://mirror.example.com"},
+		Target:         "release",
+		Labels:         []string{"org.opencontainers.image.vendor=locke-codes"},
+		Platform:       "linux/arm64",
+		History:        []string{"0123456789abcdef0123456789abcdef01234567"},
+		DefaultCommand: "bs",
+		CommandAlias:   "bs",
+		Commands:       []config.CommandConfig{{Alias: "bs-lint", Command: "bs lint"}},
+		Volumes:        []config.VolumeConfig{{Host: "~/.kube", Container: "/opt/usr/home/.kube", Mode: "ro"}},
+		PassEnv:        []string{"KUBECONFIG"},
+		Env:            []string{"REGION=eu"},
+		EnvFile:        "~/.config/big-salad.env",
+		Engine:         "docker",
+		TTY:            "never",
+		KeepContainers: true,
+		UserMapping:    "none",
+		Home:           "isolated",
+		HomeFiles:      []string{".gitconfig"},
+	}
+	configFile := config.ContainerCliConfig{
+		ContainerEngine: "podman",
+		Path:            filepath.Join(t.TempDir(), "config.yaml"),
+		Projects:        []config.ProjectConfig{project},
+	}
+	if err := configFile.SaveConfig(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	loaded := config.ContainerCliConfig{Path: configFile.Path}
+	if err := loaded.LoadConfig(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if len(loaded.Projects) != 1 || !reflect.DeepEqual(loaded.Projects[0], project) {
+		t.Errorf("Output mismatch. Expected %+v but got %+v", project, loaded.Projects)
+	}
+}
+
+func TestVolumeMapping(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    config.VolumeConfig
+		expected string
+		hasError bool
+	}
+
+	hostDir := t.TempDir()
+	t.Setenv("CCLI_TEST_DIR", hostDir)
+	t.Setenv("CCLI_TEST_EMPTY", "")
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("Failed to retrieve home directory: %+v", err)
+	}
+
+	tests := []testCase{
+		{name: "Host", input: config.VolumeConfig{Host: hostDir, Container: "/data"}, expected: hostDir + ":/data"},
+		{
+			name:     "EnvHost",
+			input:    config.VolumeConfig{Host: "${CCLI_TEST_DIR}", Container: "/data", Mode: "ro"},
+			expected: hostDir + ":/data:ro",
+		},
+		{
+			name:     "UnsetEnvHost",
+			input:    config.VolumeConfig{Host: "${CCLI_TEST_UNSET}/", Container: "/data"},
+			hasError: true,
+		},
+		{
+			name:     "EmptyEnvHost",
+			input:    config.VolumeConfig{Host: "$CCLI_TEST_EMPTY/cache", Container: "/data"},
+			hasError: true,
+		},
+		{
+			name:     "HomeHost",
+			input:    config.VolumeConfig{Host: "~", Container: "/data", Mode: "ro,Z"},
+			expected: homeDir + ":/data:ro,Z",
+		},
+		{
+			name:     "Named",
+			input:    config.VolumeConfig{Name: "tool-cache", Container: "/cache", Mode: "z"},
+			expected: "tool-cache:/cache:z",
+		},
+		{
+			name:     "MissingHost",
+			input:    config.VolumeConfig{Host: hostDir + "/missing", Container: "/data"},
+			hasError: true,
+		},
+		{name: "RelativeHost", input: config.VolumeConfig{Host: "cache", Container: "/cache"}, hasError: true},
+		{
+			name:     "HostAndName",
+			input:    config.VolumeConfig{Host: hostDir, Name: "cache", Container: "/data"},
+			hasError: true,
+		},
+		{name: "NoSource", input: config.VolumeConfig{Container: "/data"}, hasError: true},
+		{name: "InvalidName", input: config.VolumeConfig{Name: "-cache", Container: "/cache"}, hasError: true},
+		{name: "RelativeContainer", input: config.VolumeConfig{Host: hostDir, Container: "data"}, hasError: true},
+		{
+			name:     "InvalidMode",
+			input:    config.VolumeConfig{Host: hostDir, Container: "/data", Mode: "readonly"},
+			hasError: true,
+		},
+		{
+			name:     "ConflictingMode",
+			input:    config.VolumeConfig{Host: hostDir, Container: "/data", Mode: "ro,rw"},
+			hasError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := test.input.Mapping()
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+			if out != test.expected {
+				t.Errorf("Output mismatch. Expected %+v but got %+v", test.expected, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+)
+
+func TestLockFile(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), config.LockFileName)
+	lockFile, err := config.LoadLockFile(lockPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if len(lockFile.Projects) != 0 {
+		t.Fatalf("Expected an empty lock file but got %+v", lockFile.Projects)
+	}
+
+	bigSalad := config.ProjectLock{
+		Name:           "big-salad",
+		Commit:         "0123456789abcdef0123456789abcdef01234567",
+		ImageID:        "sha256:1111",
+		DockerfileHash: "2222",
+		CcliVersion:    "0.3.0",
+	}
+	goWorld := config.ProjectLock{
+		Name:        "go-world",
+		ImageID:     "sha256:3333",
+		ImageDigest: "registry.example.com/go-world@sha256:4444",
+		CcliVersion: "0.3.0",
+	}
+	lockFile.SetProject(bigSalad)
+	lockFile.SetProject(goWorld)
+	bigSalad.ImageID = "sha256:5555"
+	lockFile.SetProject(bigSalad)
+	if err = lockFile.Save(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	loaded, err := config.LoadLockFile(lockPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	expected := []config.ProjectLock{bigSalad, goWorld}
+	if !reflect.DeepEqual(loaded.Projects, expected) {
+		t.Errorf("Output mismatch. Expected %+v but got %+v", expected, loaded.Projects)
+	}
+
+	loaded.RemoveProject("big-salad")
+	if loaded.GetProject("big-salad") != nil {
+		t.Errorf("Expected big-salad to be removed")
+	}
+	if got := loaded.GetProject("go-world"); got == nil || !reflect.DeepEqual(*got, goWorld) {
+		t.Errorf("Output mismatch. Expected %+v but got %+v", goWorld, got)
+	}
+}
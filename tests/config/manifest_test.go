@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+)
+
+func TestLoadManifest(t *testing.T) {
+	directory := t.TempDir()
+	content := `command: bs
+alias: bs
+dockerfile: build/Dockerfile
+context: build
+buildArgs:
+  - MIRROR=https://mirror.example.com
+volumes:
+  - host: ~/.kube
+    container: /opt/usr/home/.kube
+    mode: ro
+passEnv:
+  - KUBECONFIG
+minVersion: 0.1.0
+`
+	err := os.WriteFile(filepath.Join(directory, config.ManifestFileName), []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := config.LoadManifest(directory)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	expected := config.Manifest{
+		Command:    "bs",
+		Alias:      "bs",
+		Dockerfile: "build/Dockerfile",
+		Context:    "build",
+		BuildArgs:  []string{"MIRROR=https://mirror.example.com"},
+		Volumes:    []config.VolumeConfig{{Host: "~/.kube", Container: "/opt/usr/home/.kube", Mode: "ro"}},
+		PassEnv:    []string{"KUBECONFIG"},
+		MinVersion: "0.1.0",
+	}
+	if !reflect.DeepEqual(*manifest, expected) {
+		t.Errorf("Output mismatch. Expected %+v but got %+v", expected, *manifest)
+	}
+	if got := manifest.DockerfilePath(directory); got != filepath.Join(directory, "build/Dockerfile") {
+		t.Errorf("Dockerfile mismatch. Got %s", got)
+	}
+	if got := manifest.ContextPath(directory); got != filepath.Join(directory, "build") {
+		t.Errorf("Context mismatch. Got %s", got)
+	}
+}
+
+func TestLoadManifestMissing(t *testing.T) {
+	directory := t.TempDir()
+	manifest, err := config.LoadManifest(directory)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if got := manifest.DockerfilePath(directory); got != filepath.Join(directory, "Dockerfile") {
+		t.Errorf("Dockerfile mismatch. Got %s", got)
+	}
+	if got := manifest.ContextPath(directory); got != directory {
+		t.Errorf("Context mismatch. Got %s", got)
+	}
+}
+
+func TestLoadManifestPaths(t *testing.T) {
+	type testCase struct {
+		name     string
+		content  string
+		hasError bool
+	}
+
+	tests := []testCase{
+		{name: "Nested", content: "dockerfile: build/Dockerfile\ncontext: build\n"},
+		{name: "CurrentDirectory", content: "context: .\n"},
+		{name: "CleanedInside", content: "context: build/../src\n"},
+		{name: "ParentContext", content: "context: ../../..\n", hasError: true},
+		{name: "ParentDockerfile", content: "dockerfile: ../../x\n", hasError: true},
+		{name: "AbsoluteContext", content: "context: /\n", hasError: true},
+		{name: "AbsoluteDockerfile", content: "dockerfile: /etc/Dockerfile\n", hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			directory := t.TempDir()
+			err := os.WriteFile(filepath.Join(directory, config.ManifestFileName), []byte(test.content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = config.LoadManifest(directory)
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+		})
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	type testCase struct {
+		name       string
+		minVersion string
+		current    string
+		hasError   bool
+	}
+
+	tests := []testCase{
+		{name: "NoMinimum", minVersion: "", current: "0.1.0"},
+		{name: "Newer", minVersion: "0.1.0", current: "0.2.0"},
+		{name: "Equal", minVersion: "0.2.0", current: "0.2.0"},
+		{name: "Older", minVersion: "0.3.0", current: "0.2.0", hasError: true},
+		{name: "DevelopmentBuild", minVersion: "0.3.0", current: ""},
+		{name: "InvalidMinimum", minVersion: "latest", current: "0.2.0", hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manifest := config.Manifest{MinVersion: test.minVersion}
+			err := manifest.CheckVersion(test.current)
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+		})
+	}
+}
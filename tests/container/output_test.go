@@ -0,0 +1,24 @@
+package container
+
+import (
+	"bytes"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/container"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := container.NewPrefixWriter(&out, "[tool] ")
+	for _, chunk := range []string{"Step 1/2\nStep ", "2/2\n", "\nDone"} {
+		n, err := w.Write([]byte(chunk))
+		if err != nil || n != len(chunk) {
+			t.Fatalf("Unexpected write of %d bytes: %+v", n, err)
+		}
+	}
+
+	expected := "[tool] Step 1/2\n[tool] Step 2/2\n[tool] \n[tool] Done"
+	if out.String() != expected {
+		t.Errorf("Output mismatch. Expected %q but got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"gitlab.com/locke-codes/container-cli/internal/engine"
+)
+
+// openTerminal opens a pseudo terminal of the given size and returns its controlling and its terminal side.
+func openTerminal(t *testing.T, rows, cols uint16) (*os.File, *os.File) {
+	t.Helper()
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("No pseudo terminals available: %v", err)
+	}
+	t.Cleanup(func() { _ = ptmx.Close() })
+	if err = unix.IoctlSetPointerInt(int(ptmx.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	index, err := unix.IoctlGetInt(int(ptmx.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	pts, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", index), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	t.Cleanup(func() { _ = pts.Close() })
+	if err = unix.IoctlSetWinsize(int(pts.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Row: rows, Col: cols}); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	return ptmx, pts
+}
+
+func TestAPIRunTTY(t *testing.T) {
+	_, pts := openTerminal(t, 40, 120)
+	var created map[string]any
+	var resizeQuery string
+	rawDuringRun := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /containers/create", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		_, _ = fmt.Fprint(w, `{"Id":"c3"}`)
+	})
+	mux.HandleFunc("POST /containers/c3/attach", func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Unexpected error: %+v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		// With a TTY the output is not multiplexed
+		_, _ = buf.WriteString("HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\nraw output\r\n")
+		_ = buf.Flush()
+	})
+	mux.HandleFunc("POST /containers/c3/start", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /containers/c3/resize", func(w http.ResponseWriter, r *http.Request) {
+		resizeQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /containers/c3/wait", func(w http.ResponseWriter, r *http.Request) {
+		termios, err := unix.IoctlGetTermios(int(pts.Fd()), unix.TCGETS)
+		rawDuringRun = err == nil && termios.Lflag&unix.ICANON == 0
+		_, _ = fmt.Fprint(w, `{"StatusCode":0}`)
+	})
+	backend := newAPIServer(t, mux)
+
+	code, err := backend.Run(engine.RunOptions{Image: "tool:latest", TTY: true, Interactive: true, Stdin: pts, Stdout: pts})
+	if err != nil || code != 0 {
+		t.Fatalf("Unexpected exit code %d: %+v", code, err)
+	}
+	if created["Tty"] != true {
+		t.Errorf("Expected a TTY, got %+v", created)
+	}
+	if !strings.Contains(resizeQuery, "h=40") || !strings.Contains(resizeQuery, "w=120") {
+		t.Errorf("Expected the TTY to be resized to 120x40, got %q", resizeQuery)
+	}
+	if !rawDuringRun {
+		t.Errorf("Expected the terminal to be in raw mode while the container runs")
+	}
+	termios, err := unix.IoctlGetTermios(int(pts.Fd()), unix.TCGETS)
+	if err != nil || termios.Lflag&unix.ICANON == 0 {
+		t.Errorf("Expected the terminal mode to be restored after the run: %v", err)
+	}
+}
+
+func TestAPIRunInterrupt(t *testing.T) {
+	killed := make(chan string, 1)
+	removed := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /containers/create", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"Id":"c4"}`)
+	})
+	mux.HandleFunc("POST /containers/c4/attach", func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Unexpected error: %+v", err)
+			return
+		}
+		go func() {
+			defer func() { _ = conn.Close() }()
+			_, _ = buf.WriteString("HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+			_ = buf.Flush()
+			// Ctrl-C while the container runs ends the container, not ccli
+			_ = unix.Kill(os.Getpid(), unix.SIGINT)
+			select {
+			case <-killed:
+			case <-time.After(5 * time.Second):
+				t.Errorf("Expected the interrupt to be passed to the container")
+			}
+		}()
+	})
+	mux.HandleFunc("POST /containers/c4/start", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	var signalName string
+	mux.HandleFunc("POST /containers/c4/kill", func(w http.ResponseWriter, r *http.Request) {
+		signalName = r.URL.Query().Get("signal")
+		w.WriteHeader(http.StatusNoContent)
+		killed <- signalName
+	})
+	mux.HandleFunc("POST /containers/c4/wait", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"StatusCode":130}`)
+	})
+	mux.HandleFunc("DELETE /containers/c4", func(w http.ResponseWriter, r *http.Request) {
+		removed = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	backend := newAPIServer(t, mux)
+
+	code, err := backend.Run(engine.RunOptions{Image: "tool:latest", Remove: true, Stdout: io.Discard, Stderr: io.Discard})
+	if err != nil || code != 130 {
+		t.Fatalf("Unexpected exit code %d: %+v", code, err)
+	}
+	if signalName != "SIGINT" || !removed {
+		t.Errorf("Expected SIGINT to be passed and the container to be removed, got %q and %t", signalName, removed)
+	}
+}
@@ -0,0 +1,333 @@
+package engine
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/engine"
+)
+
+// apiVersion is the API version the stand-in for the Docker Engine API supports.
+const apiVersion = "1.41"
+
+// newAPIServer starts a stand-in for the Docker Engine API on a unix socket and returns the engine talking to it.
+// The stand-in answers pings with its API version, and hands the requests in that version to handler.
+func newAPIServer(t *testing.T, handler http.Handler) engine.Engine {
+	t.Helper()
+	// Socket paths are limited in length, so the socket is not created in t.TempDir
+	dir, err := os.MkdirTemp("", "ccli")
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "engine.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	versioned := http.StripPrefix("/v"+apiVersion, handler)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/_ping":
+			w.Header().Set("Api-Version", apiVersion)
+			_, _ = fmt.Fprint(w, "OK")
+		case strings.HasPrefix(r.URL.Path, "/v"+apiVersion+"/"):
+			versioned.ServeHTTP(w, r)
+		default:
+			t.Errorf("Expected a request in API version %s, got %s", apiVersion, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return engine.NewAPI(engine.DockerAPI, "unix://"+socket, false)
+}
+
+// frame encodes output of a container in the multiplexed format of the attach endpoint.
+func frame(stream byte, data string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(data)))
+	return append(header, data...)
+}
+
+func TestAPIImages(t *testing.T) {
+	var removed string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"SecurityOptions":["name=seccomp","name=rootless"]}`)
+	})
+	mux.HandleFunc("GET /images/{name...}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("name") != "registry.example.com/tool:1.0/json" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"message":"No such image"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"Id":"sha256:abc","RepoDigests":["registry.example.com/tool@sha256:def"]}`)
+	})
+	mux.HandleFunc("GET /containers/json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"Id":"c1","Names":["/ccli-tool-42"],"Image":"tool:latest","State":"exited",`+
+			`"Labels":{"ccli.project":"tool"}}]`)
+	})
+	mux.HandleFunc("DELETE /images/{name...}", func(w http.ResponseWriter, r *http.Request) {
+		removed = r.URL.EscapedPath()
+		_, _ = fmt.Fprint(w, `[]`)
+	})
+	backend := newAPIServer(t, mux)
+
+	status := backend.Probe()
+	if !status.Usable() || !status.Rootless {
+		t.Errorf("Expected a running rootless engine, got %+v", status)
+	}
+	info, err := backend.Inspect("registry.example.com/tool:1.0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if info.ID != "sha256:abc" || len(info.RepoDigests) != 1 {
+		t.Errorf("Unexpected image details %+v", info)
+	}
+	if backend.ImageExists("missing") {
+		t.Errorf("Expected a missing image to not exist")
+	}
+	if _, err = backend.Inspect("missing"); err == nil || !strings.Contains(err.Error(), "No such image") {
+		t.Errorf("Expected the message of the API, got %v", err)
+	}
+	if err = backend.RemoveImage("tool:latest"); err != nil || removed != "/images/tool:latest" {
+		t.Errorf("Expected tool:latest to be removed, got %q and %v", removed, err)
+	}
+	// Image names are a single path segment of the endpoint
+	err = backend.RemoveImage("registry.example.com/tool:1.0")
+	if err != nil || removed != "/images/registry.example.com%2Ftool:1.0" {
+		t.Errorf("Expected the image name to be escaped, got %q and %v", removed, err)
+	}
+	containers, err := backend.ListContainers()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	expected := []engine.ContainerInfo{{
+		ID: "c1", Name: "ccli-tool-42", Image: "tool:latest", State: "exited",
+		Labels: map[string]string{"ccli.project": "tool"},
+	}}
+	if !reflect.DeepEqual(containers, expected) {
+		t.Errorf("Output mismatch. Expected %+v but got %+v", expected, containers)
+	}
+}
+
+func TestAPIBuild(t *testing.T) {
+	contextDir := t.TempDir()
+	files := map[string]string{
+		"Dockerfile":       "FROM scratch\n",
+		".dockerignore":    "# Keep the context small\nDockerfile\n*.log\nnode_modules\ndocs/**\n!docs/keep.md\n",
+		"main.go":          "package main\n",
+		"build.log":        "old output\n",
+		"node_modules/a":   "dependency\n",
+		"docs/drop.md":     "dropped\n",
+		"docs/keep.md":     "kept\n",
+		"docs/sub/drop.md": "dropped\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(contextDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+	}
+
+	var sent []string
+	var query map[string][]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /build", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		tr := tar.NewReader(r.Body)
+		for {
+			header, err := tr.Next()
+			if err != nil {
+				break
+			}
+			if header.Typeflag == tar.TypeReg {
+				sent = append(sent, header.Name)
+			}
+		}
+		_, _ = fmt.Fprint(w, `{"stream":"Step 1/1 : FROM scratch\n"}`+"\n"+`{"stream":"Successfully built\n"}`)
+	})
+	backend := newAPIServer(t, mux)
+
+	err := backend.Build(engine.BuildOptions{
+		Dockerfile: filepath.Join(contextDir, "Dockerfile"),
+		Context:    contextDir,
+		Tags:       []string{"tool", "tool:0123456789ab"},
+		BuildArgs:  []string{"DEBUG=1"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	// The Dockerfile and the .dockerignore file are sent even when they are ignored
+	expected := []string{".dockerignore", "Dockerfile", "docs/keep.md", "main.go"}
+	if !reflect.DeepEqual(sent, expected) {
+		t.Errorf("Output mismatch. Expected %+v but got %+v", expected, sent)
+	}
+	if strings.Join(query["t"], ",") != "tool,tool:0123456789ab" || query["buildargs"][0] != `{"DEBUG":"1"}` {
+		t.Errorf("Unexpected build query %+v", query)
+	}
+}
+
+func TestAPIRun(t *testing.T) {
+	var created map[string]any
+	started := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /containers/create", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		_, _ = fmt.Fprint(w, `{"Id":"c1"}`)
+	})
+	mux.HandleFunc("POST /containers/c1/attach", func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Unexpected error: %+v", err)
+			return
+		}
+		go func() {
+			defer func() { _ = conn.Close() }()
+			_, _ = buf.WriteString("HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+			_ = buf.Flush()
+			// Echo the input back on stdout once it ends
+			input, _ := io.ReadAll(buf)
+			_, _ = conn.Write(frame(1, "got "+string(input)))
+			_, _ = conn.Write(frame(2, "warning\n"))
+		}()
+	})
+	mux.HandleFunc("POST /containers/c1/start", func(w http.ResponseWriter, r *http.Request) {
+		started = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /containers/c1/wait", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"StatusCode":3}`)
+	})
+	removed := false
+	mux.HandleFunc("DELETE /containers/c1", func(w http.ResponseWriter, r *http.Request) {
+		removed = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	backend := newAPIServer(t, mux)
+
+	t.Setenv("CCLI_TEST_PASS", "passed")
+	envFile := filepath.Join(t.TempDir(), "tool.env")
+	if err := os.WriteFile(envFile, []byte("# Defaults\nREGION=eu\n\n  TOKEN=a=b\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	code, err := backend.Run(engine.RunOptions{
+		Image:       "tool:latest",
+		Command:     []string{"tool", "format"},
+		Env:         []string{"IN_DOCKER=true", "CCLI_TEST_PASS", "CCLI_TEST_UNSET"},
+		Volumes:     []string{"/home/user/work:/opt/context"},
+		Workdir:     "/opt/context",
+		EnvFiles:    []string{envFile},
+		Interactive: true,
+		Remove:      true,
+		Stdin:       strings.NewReader("input"),
+		Stdout:      &stdout,
+		Stderr:      &stderr,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if code != 3 || !started || !removed {
+		t.Errorf("Expected the started container to exit with 3 and be removed, got %d", code)
+	}
+	if stdout.String() != "got input" || stderr.String() != "warning\n" {
+		t.Errorf("Unexpected output %q and %q", stdout.String(), stderr.String())
+	}
+	env := fmt.Sprint(created["Env"])
+	if env != "[REGION=eu TOKEN=a=b IN_DOCKER=true CCLI_TEST_PASS=passed]" || created["WorkingDir"] != "/opt/context" {
+		t.Errorf("Unexpected container config %+v", created)
+	}
+}
+
+func TestAPIRunTTYNotTerminal(t *testing.T) {
+	var created map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /containers/create", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		_, _ = fmt.Fprint(w, `{"Id":"c2"}`)
+	})
+	mux.HandleFunc("POST /containers/c2/attach", func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Unexpected error: %+v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = buf.WriteString("HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+		_, _ = buf.Write(frame(1, "output\n"))
+		_ = buf.Flush()
+	})
+	mux.HandleFunc("POST /containers/c2/start", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /containers/c2/wait", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"StatusCode":0}`)
+	})
+	backend := newAPIServer(t, mux)
+
+	// Output that does not go to a terminal gets no TTY, so it stays multiplexed
+	var stdout bytes.Buffer
+	code, err := backend.Run(engine.RunOptions{Image: "tool:latest", TTY: true, Stdout: &stdout})
+	if err != nil || code != 0 {
+		t.Fatalf("Unexpected exit code %d: %+v", code, err)
+	}
+	if stdout.String() != "output\n" {
+		t.Errorf("Unexpected output %q", stdout.String())
+	}
+	if created["Tty"] != nil {
+		t.Errorf("Expected no TTY, got %+v", created)
+	}
+}
+
+func TestAPIPull(t *testing.T) {
+	type testCase struct {
+		name     string
+		image    string
+		expected string // Query of the pull request
+	}
+
+	tests := []testCase{
+		{name: "Untagged", image: "alpine", expected: "fromImage=alpine&tag=latest"},
+		{name: "Tagged", image: "alpine:3", expected: "fromImage=alpine%3A3"},
+		{name: "Digest", image: "alpine@sha256:abc", expected: "fromImage=alpine%40sha256%3Aabc"},
+		{name: "RegistryPort", image: "localhost:5000/tool", expected: "fromImage=localhost%3A5000%2Ftool&tag=latest"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var query string
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /images/create", func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				_, _ = fmt.Fprint(w, `{"status":"Pulled"}`)
+			})
+			backend := newAPIServer(t, mux)
+
+			if err := backend.Pull(test.image, io.Discard); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if query != test.expected {
+				t.Errorf("Output mismatch. Expected %+v but got %+v", test.expected, query)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/engine"
+)
+
+// writeClient writes a fake engine client to dir that prints output for its info command and exits with code.
+func writeClient(t *testing.T, dir, name, output string, code int) {
+	t.Helper()
+	script := "#!/bin/sh\necho '" + output + "'\nexit " + strconv.Itoa(code) + "\n"
+	err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	writeClient(t, dir, "docker", "Cannot connect to the Docker daemon", 1)
+	writeClient(t, dir, "podman", "true", 0)
+	t.Setenv("PATH", dir)
+
+	statuses := engine.Detect()
+	if len(statuses) != len(engine.Names) {
+		t.Fatalf("Expected a status for every engine, got %+v", statuses)
+	}
+
+	docker, podman, nerdctl := statuses[0], statuses[1], statuses[2]
+	if !docker.Installed || docker.Running || docker.Check() == nil {
+		t.Errorf("Expected docker to be installed but not running, got %+v", docker)
+	}
+	if !podman.Usable() || !podman.Rootless || podman.Check() != nil {
+		t.Errorf("Expected podman to be running rootless, got %+v", podman)
+	}
+	if nerdctl.Installed || nerdctl.Check() == nil {
+		t.Errorf("Expected nerdctl to be missing, got %+v", nerdctl)
+	}
+
+	if err := engine.Check("podman"); err != nil {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	if err := engine.Check("docker"); err == nil {
+		t.Errorf("Expected error for an engine that is not running")
+	}
+}
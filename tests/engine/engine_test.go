@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/engine"
+)
+
+func TestNew(t *testing.T) {
+	for _, name := range engine.Names {
+		backend, err := engine.New(name)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %+v", name, err)
+		}
+		if backend.Name() != name {
+			t.Errorf("Expected engine %s but got %s", name, backend.Name())
+		}
+	}
+	if _, err := engine.New("runc"); err == nil {
+		t.Errorf("Expected error for an unsupported engine")
+	}
+}
+
+func TestRunArgs(t *testing.T) {
+	type testCase struct {
+		name     string
+		engine   engine.Engine
+		input    engine.RunOptions
+		expected []string
+	}
+
+	opts := engine.RunOptions{
+		Image:      "tool:latest",
+		Command:    []string{"tool", "lint"},
+		Env:        []string{"IN_DOCKER=true", "DISPLAY"},
+		Volumes:    []string{"/home/user/work:/opt/context"},
+		Workdir:    "/opt/context",
+		User:       "1000:1000",
+		KeepUserID: true,
+	}
+
+	tests := []testCase{
+		{
+			name:   "Docker",
+			engine: engine.NewDocker(),
+			input:  opts,
+			expected: []string{
+				"run", "--env", "IN_DOCKER=true", "--env", "DISPLAY", "--volume", "/home/user/work:/opt/context",
+				"--user", "1000:1000", "--workdir", "/opt/context", "tool:latest", "tool", "lint",
+			},
+		},
+		{
+			name:   "Podman",
+			engine: engine.NewPodman(),
+			input:  opts,
+			expected: []string{
+				"run", "--env", "IN_DOCKER=true", "--env", "DISPLAY", "--volume", "/home/user/work:/opt/context",
+				"--userns=keep-id", "--workdir", "/opt/context", "tool:latest", "tool", "lint",
+			},
+		},
+		{
+			name:     "Terminal",
+			engine:   engine.NewDocker(),
+			input:    engine.RunOptions{Image: "tool", Interactive: true, TTY: true},
+			expected: []string{"run", "--interactive", "--tty", "tool"},
+		},
+		{
+			name:   "Removed",
+			engine: engine.NewPodman(),
+			input: engine.RunOptions{
+				Image: "tool", Remove: true, Name: "ccli-tool-42", Labels: []string{"ccli.project=tool"},
+			},
+			expected: []string{"run", "--rm", "--name", "ccli-tool-42", "--label", "ccli.project=tool", "tool"},
+		},
+		{
+			name:   "EnvFiles",
+			engine: engine.NewDocker(),
+			input: engine.RunOptions{
+				Image: "tool", Env: []string{"REGION=eu"}, EnvFiles: []string{"/home/user/tool.env"},
+			},
+			expected: []string{"run", "--env-file", "/home/user/tool.env", "--env", "REGION=eu", "tool"},
+		},
+		{
+			name:     "Minimal",
+			engine:   engine.NewNerdctl(),
+			input:    engine.RunOptions{Image: "tool"},
+			expected: []string{"run", "tool"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := test.engine.(engine.CommandLine).RunArgs(test.input)
+			if !reflect.DeepEqual(out, test.expected) {
+				t.Errorf("Output mismatch. Expected %+v but got %+v", test.expected, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package gitter
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/gitter"
+)
+
+// git runs a git command in dir and fails the test if it does not succeed.
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, out)
+	}
+	return string(out)
+}
+
+// commitFile writes a file into the repository at dir and commits it.
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "add", name)
+	git(t, dir, "commit", "-m", "update "+name)
+}
+
+func TestUpdate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	upstream := filepath.Join(t.TempDir(), "upstream")
+	if err := os.MkdirAll(upstream, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(t, upstream, "init", "--initial-branch=main")
+	commitFile(t, upstream, "Dockerfile", "FROM scratch\n")
+
+	destination := filepath.Join(t.TempDir(), "project")
+	client := gitter.NewGitter("project", "file://"+upstream, "", destination)
+	if err := client.Clone(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	cloned, err := client.Commit()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	if err = client.Update(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	unchanged, _ := client.Commit()
+	if unchanged != cloned {
+		t.Errorf("Commit mismatch. Expected %s but got %s", cloned, unchanged)
+	}
+
+	commitFile(t, upstream, "README.md", "hello\n")
+	if err = client.Update(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	updated, _ := client.Commit()
+	if updated == cloned {
+		t.Errorf("Expected commit to change after update but it stayed at %s", cloned)
+	}
+	if _, err = os.Stat(filepath.Join(destination, "README.md")); err != nil {
+		t.Errorf("Expected README.md to be fast-forwarded into the checkout: %+v", err)
+	}
+
+	if err = client.Reset("main", cloned); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	reset, _ := client.Commit()
+	if reset != cloned {
+		t.Errorf("Commit mismatch. Expected %s but got %s", cloned, reset)
+	}
+}
+
+func TestResetRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	upstream := filepath.Join(t.TempDir(), "upstream")
+	if err := os.MkdirAll(upstream, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(t, upstream, "init", "--initial-branch=main")
+	commitFile(t, upstream, "Dockerfile", "FROM scratch\n")
+	git(t, upstream, "checkout", "-b", "dev")
+	commitFile(t, upstream, "README.md", "dev\n")
+	git(t, upstream, "checkout", "main")
+
+	destination := filepath.Join(t.TempDir(), "project")
+	client := gitter.NewGitter("project", "file://"+upstream, "", destination)
+	if err := client.Clone(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	head, _ := client.Head()
+	cloned, _ := client.Commit()
+	if head != "main" {
+		t.Fatalf("Expected the checkout to be on main, got %s", head)
+	}
+
+	// A failed update to another branch goes back to the previous branch without moving the new one
+	client.Ref = "dev"
+	if err := client.Update(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	dev, _ := client.Commit()
+	if err := client.Reset(head, cloned); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if reset, _ := client.Head(); reset != "main" {
+		t.Errorf("Expected the checkout to be back on main, got %s", reset)
+	}
+	if branch := strings.TrimSpace(git(t, destination, "rev-parse", "dev")); branch != dev {
+		t.Errorf("Expected dev to stay at %s, got %s", dev, branch)
+	}
+
+	// Following the default branch leaves any other branch
+	git(t, destination, "checkout", "dev")
+	client.Ref = ""
+	if err := client.Update(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if updated, _ := client.Commit(); updated != cloned {
+		t.Errorf("Expected the default branch at %s, got %s", cloned, updated)
+	}
+}
+
+func TestCloneForcedGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	upstream := filepath.Join(t.TempDir(), "upstream")
+	if err := os.MkdirAll(upstream, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(t, upstream, "init", "--initial-branch=main")
+	commitFile(t, upstream, "Dockerfile", "FROM scratch\n")
+
+	destination := filepath.Join(t.TempDir(), "project")
+	client := gitter.NewGitter("project", "git::file://"+upstream, "", destination)
+	if client.Url.String() != "file://"+upstream {
+		t.Errorf("Expected the git:: prefix to be stripped, got %s", client.Url)
+	}
+	if err := client.Clone(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if _, err := os.Stat(filepath.Join(destination, "Dockerfile")); err != nil {
+		t.Errorf("Expected the Dockerfile to be cloned: %+v", err)
+	}
+}
+
+func TestUpdatePinned(t *testing.T) {
+	type testCase struct {
+		name    string
+		ref     string
+		follows bool
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	tests := []testCase{
+		{name: "Branch", ref: "release", follows: true},
+		{name: "Tag", ref: "v1.0.0", follows: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			upstream := filepath.Join(t.TempDir(), "upstream")
+			if err := os.MkdirAll(upstream, 0755); err != nil {
+				t.Fatal(err)
+			}
+			git(t, upstream, "init", "--initial-branch=main")
+			commitFile(t, upstream, "Dockerfile", "FROM scratch\n")
+			git(t, upstream, "tag", "v1.0.0")
+			git(t, upstream, "branch", "release")
+
+			client := gitter.NewGitter("project", "file://"+upstream, test.ref, filepath.Join(t.TempDir(), "project"))
+			if err := client.Clone(); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			cloned, _ := client.Commit()
+
+			git(t, upstream, "checkout", "release")
+			commitFile(t, upstream, "README.md", "hello\n")
+			if err := client.Update(); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			updated, _ := client.Commit()
+			if test.follows && updated == cloned {
+				t.Errorf("Expected %s to follow the branch but it stayed at %s", test.ref, cloned)
+			}
+			if !test.follows && updated != cloned {
+				t.Errorf("Expected %s to stay at %s but it moved to %s", test.ref, cloned, updated)
+			}
+		})
+	}
+}
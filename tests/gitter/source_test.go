@@ -0,0 +1,120 @@
+package gitter
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/gitter"
+)
+
+func TestDetectSource(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    string
+		expected string
+		hasError bool
+	}
+
+	tests := []testCase{
+		{name: "GitHttps", input: "https://gitlab.com/locke-codes/go-world.git", expected: config.SourceGit},
+		{name: "GitSsh", input: "ssh://git@gitlab.com/locke-codes/go-world.git", expected: config.SourceGit},
+		{name: "ForcedGit", input: "git::https://example.com/tool", expected: config.SourceGit},
+		{name: "TarGz", input: "https://example.com/releases/tool-1.0.0.tar.gz", expected: config.SourceHTTP},
+		{name: "Zip", input: "https://example.com/releases/tool.zip", expected: config.SourceHTTP},
+		{name: "ArchiveQuery", input: "https://example.com/download?archive=tar.gz", expected: config.SourceHTTP},
+		{name: "S3", input: "s3::https://s3.amazonaws.com/bucket/tool.tar.gz", expected: config.SourceS3},
+		{name: "GCS", input: "gcs::https://www.googleapis.com/storage/v1/bucket/tool.tar.gz", expected: config.SourceGCS},
+		{name: "File", input: "file:///opt/releases/tool.tar.gz", expected: config.SourceFile},
+		{name: "PlainHttp", input: "http://example.com/tool.git", hasError: true},
+		{name: "PlainHttpArchive", input: "http://example.com/releases/tool.tar.gz", hasError: true},
+		{name: "ForcedPlainHttp", input: "http::http://example.com/download", hasError: true},
+		{
+			name:     "PlainHttpChecksum",
+			input:    "http://example.com/releases/tool.tar.gz?checksum=sha256:" + strings.Repeat("0", 64),
+			expected: config.SourceHTTP,
+		},
+		{name: "PlainHttpChecksumNoArchive", input: "http://example.com/tool.git?checksum=sha256:00", hasError: true},
+		{name: "UnknownScheme", input: "ftp://example.com/tool.tar.gz", hasError: true},
+		{name: "UnknownForced", input: "hg::https://example.com/tool", hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := gitter.DetectSource(test.input)
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+			if out != test.expected {
+				t.Errorf("Output mismatch. Expected %+v but got %+v", test.expected, out)
+			}
+		})
+	}
+}
+
+// writeArchive writes a tar.gz archive containing a single Dockerfile with the given content.
+func writeArchive(t *testing.T, archivePath, content string) {
+	t.Helper()
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	header := &tar.Header{Name: "Dockerfile", Mode: 0644, Size: int64(len(content))}
+	if err = tarWriter.WriteHeader(header); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tarWriter.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err = tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "tool.tar.gz")
+	destination := filepath.Join(t.TempDir(), "tool")
+	client := gitter.NewDownloader("tool", config.SourceFile, "file://"+archivePath, destination)
+
+	writeArchive(t, archivePath, "FROM scratch\n")
+	if err := client.Download(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	assertDockerfile(t, destination, "FROM scratch\n")
+
+	writeArchive(t, archivePath, "FROM alpine\n")
+	if err := client.Download(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	assertDockerfile(t, destination, "FROM alpine\n")
+
+	if err := client.Restore(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	assertDockerfile(t, destination, "FROM scratch\n")
+}
+
+// assertDockerfile fails the test if the Dockerfile in directory does not have the expected content.
+func assertDockerfile(t *testing.T, directory, expected string) {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(directory, "Dockerfile"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if string(content) != expected {
+		t.Errorf("Output mismatch. Expected %q but got %q", expected, string(content))
+	}
+}
@@ -0,0 +1,62 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestInstallAliasConflicts(t *testing.T) {
+	type testCase struct {
+		name     string
+		manifest string
+		expected string
+	}
+
+	tests := []testCase{
+		{name: "BinaryName", manifest: "command: tool\nalias: ccli\n", expected: "reserved for the ccli binary"},
+		{
+			name:     "OtherBinaryName",
+			manifest: "command: tool\ncommands:\n  - alias: container-cli\n    command: tool\n",
+			expected: "reserved for the ccli binary",
+		},
+		{name: "OtherProject", manifest: "command: tool\nalias: bs\n", expected: "already used by project big-salad"},
+		{name: "ExistingFile", manifest: "command: tool\nalias: git\n", expected: "was not installed by ccli"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useConfigDir(t)
+			configFile := config.ContainerCliConfig{
+				ContainerEngine: "docker",
+				Path:            globals.DefaultContainerCliConfigPath,
+				Projects:        []config.ProjectConfig{{Name: "big-salad", Source: config.SourceImage, CommandAlias: "bs"}},
+			}
+			if err := configFile.SaveConfig(); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			binDir := globals.LocalBinDirectory
+			t.Cleanup(func() { globals.LocalBinDirectory = binDir })
+			globals.LocalBinDirectory = t.TempDir()
+			if err := os.WriteFile(filepath.Join(globals.LocalBinDirectory, "git"), []byte("#!/bin/sh\n"), 0755); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			projectDir := t.TempDir()
+			manifestPath := filepath.Join(projectDir, config.ManifestFileName)
+			if err := os.WriteFile(manifestPath, []byte(test.manifest), 0644); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			project := install.Project{Name: "tool", Source: config.SourceLocal, LocalPath: projectDir}
+			err := project.Install()
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("Expected an error containing %q, got %v", test.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/container"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestBuildContainerLogs(t *testing.T) {
+	type testCase struct {
+		name     string
+		project  func(t *testing.T) install.Project
+		expected string
+	}
+
+	tests := []testCase{
+		{
+			name: "Build",
+			project: func(t *testing.T) install.Project {
+				projectDir := t.TempDir()
+				if err := os.WriteFile(filepath.Join(projectDir, "Dockerfile"), []byte("FROM scratch\n"), 0644); err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+				return install.Project{Name: "tool", Source: config.SourceLocal, LocalPath: projectDir, Quiet: true}
+			},
+			expected: "build -f ",
+		},
+		{
+			name: "Pull",
+			project: func(t *testing.T) install.Project {
+				return install.Project{Name: "tool", Source: config.SourceImage, Image: "tool:1.0", Quiet: true}
+			},
+			expected: "pull tool:1.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useFakeEngine(t)
+			project := test.project(t)
+			// Builds that start in the same second keep a log each
+			for range 2 {
+				if err := project.BuildContainer(); err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+			}
+			logs, err := filepath.Glob(filepath.Join(globals.LogDirectory, "tool", "*.log"))
+			if err != nil || len(logs) != 2 {
+				t.Fatalf("Expected 2 logs, got %+v: %v", logs, err)
+			}
+			for _, logPath := range logs {
+				content, err := os.ReadFile(logPath)
+				if err != nil || !strings.Contains(string(content), test.expected) {
+					t.Errorf("Expected %q in the log %s, got %q: %v", test.expected, logPath, content, err)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildContainerPrebuilt(t *testing.T) {
+	type testCase struct {
+		name      string
+		image     string
+		manifest  string // Manifest of a local project without a Dockerfile, used when image is empty
+		pullError string
+		expected  string // Image reference expected to be pulled and run
+		hasError  bool
+	}
+
+	digest := "registry.example.com/tool@sha256:" + strings.Repeat("0", 64)
+	tests := []testCase{
+		{name: "Tag", image: "registry.example.com/tool:1.0", expected: "registry.example.com/tool:1.0"},
+		{name: "Digest", image: digest, expected: digest},
+		{
+			name:     "Manifest",
+			manifest: "image: registry.example.com/tool:2.0\ncommand: tool\n",
+			expected: "registry.example.com/tool:2.0",
+		},
+		{name: "PullFails", image: "registry.example.com/tool:1.0", pullError: "manifest unknown", hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := useFakeEngine(t)
+			if test.pullError != "" {
+				t.Setenv("CCLI_TEST_PULL_ERROR", test.pullError)
+			}
+			project := install.Project{
+				Name:           "tool",
+				Source:         config.SourceImage,
+				Image:          test.image,
+				DefaultCommand: "tool",
+				Quiet:          true,
+			}
+			if test.manifest != "" {
+				projectDir := t.TempDir()
+				manifestPath := filepath.Join(projectDir, config.ManifestFileName)
+				if err := os.WriteFile(manifestPath, []byte(test.manifest), 0644); err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+				project = install.Project{Name: "tool", Source: config.SourceLocal, LocalPath: projectDir, Quiet: true}
+			}
+
+			err := project.Install()
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+			content, _ := os.ReadFile(calls)
+			if strings.Contains(string(content), "build ") {
+				t.Errorf("Expected a prebuilt image to not be built, got %q", content)
+			}
+			if test.hasError {
+				if !strings.Contains(err.Error(), "error pulling image") {
+					t.Errorf("Expected a pull error, got %v", err)
+				}
+				return
+			}
+			if !strings.Contains(string(content), "pull "+test.expected+"\n") {
+				t.Errorf("Expected %s to be pulled, got %q", test.expected, content)
+			}
+
+			// The command runs the pulled reference
+			configFile, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			containerObj, err := container.NewContainer(configFile.GetProject("tool"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if containerObj.Image() != test.expected {
+				t.Errorf("Expected the command to run %s, got %s", test.expected, containerObj.Image())
+			}
+		})
+	}
+}
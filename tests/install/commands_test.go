@@ -0,0 +1,48 @@
+package install
+
+import (
+	"reflect"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestParseCommands(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    []string
+		expected []config.CommandConfig
+		hasError bool
+	}
+
+	tests := []testCase{
+		{name: "Empty", input: nil, expected: nil},
+		{
+			name:  "Multiple",
+			input: []string{"tflint=tflint", "tfsec=tfsec --no-color"},
+			expected: []config.CommandConfig{
+				{Alias: "tflint", Command: "tflint"},
+				{Alias: "tfsec", Command: "tfsec --no-color"},
+			},
+		},
+		{name: "MissingCommand", input: []string{"tflint="}, hasError: true},
+		{name: "MissingSeparator", input: []string{"tflint"}, hasError: true},
+		{name: "InvalidAlias", input: []string{"tf lint=tflint"}, hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := install.ParseCommands(test.input)
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+			if !reflect.DeepEqual(out, test.expected) {
+				t.Errorf("Output mismatch. Expected %+v but got %+v", test.expected, out)
+			}
+		})
+	}
+}
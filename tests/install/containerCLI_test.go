@@ -0,0 +1,59 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestSetEngineRootless(t *testing.T) {
+	type testCase struct {
+		name     string
+		engine   string
+		info     string   // Output of the info command of the fake client
+		recorded []string // Engines recorded as rootless before
+		expected []string
+	}
+
+	tests := []testCase{
+		{name: "RootlessPodman", engine: "podman", info: "true", expected: []string{"podman"}},
+		{name: "RootfulPodman", engine: "podman", info: "false"},
+		{name: "NoLongerRootless", engine: "podman", info: "false", recorded: []string{"docker", "podman"}, expected: []string{"docker"}},
+		{name: "RootlessDocker", engine: "docker", info: "name=seccomp\\nname=rootless", expected: []string{"docker"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useFakeEngine(t)
+			recorded := config.NewContainerCliConfig("docker")
+			recorded.RootlessEngines = test.recorded
+			if err := recorded.SaveConfig(); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			dir := t.TempDir()
+			script := "#!/bin/sh\nprintf '" + test.info + "\\n'\n"
+			if err := os.WriteFile(filepath.Join(dir, test.engine), []byte(script), 0755); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+			if err := install.SetEngine(test.engine); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			configFile, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if configFile.ContainerEngine != test.engine {
+				t.Errorf("Expected the engine %s, got %s", test.engine, configFile.ContainerEngine)
+			}
+			if !slices.Equal(configFile.RootlessEngines, test.expected) {
+				t.Errorf("Output mismatch. Expected %+v but got %+v", test.expected, configFile.RootlessEngines)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package install
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestWriteProjectList(t *testing.T) {
+	type testCase struct {
+		name     string
+		output   string
+		expected []string
+		hasError bool
+	}
+
+	statuses := []install.ProjectStatus{
+		{
+			Name:           "big-salad",
+			Alias:          "bs",
+			Path:           "/home/user/.local/share/big-salad",
+			DefaultCommand: "bs",
+			ImageName:      "big-salad",
+			ScriptExists:   true,
+			ImageExists:    false,
+		},
+	}
+
+	tests := []testCase{
+		{name: "Table", output: "table", expected: []string{"NAME", "big-salad", "bs", "true", "false"}},
+		{name: "Default", output: "", expected: []string{"NAME", "big-salad"}},
+		{name: "JSON", output: "json", expected: []string{`"name": "big-salad"`, `"scriptExists": true`, `"imageExists": false`}},
+		{name: "YAML", output: "yaml", expected: []string{"name: big-salad", "alias: bs", "imageExists: false"}},
+		{name: "Invalid", output: "xml", hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := install.WriteProjectList(&buf, statuses, test.output)
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+			for _, expected := range test.expected {
+				if !strings.Contains(buf.String(), expected) {
+					t.Errorf("Output mismatch. Expected %q in %q", expected, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestInstallLocal(t *testing.T) {
+	type testCase struct {
+		name       string
+		files      map[string]string
+		missing    bool
+		dockerfile string // Dockerfile the build is expected to use, relative to the project directory
+		hasError   bool
+	}
+
+	tests := []testCase{
+		{name: "Dockerfile", files: map[string]string{"Dockerfile": "FROM scratch\n"}, dockerfile: "Dockerfile"},
+		{
+			name: "Manifest",
+			files: map[string]string{
+				"build/Dockerfile":      "FROM scratch\n",
+				config.ManifestFileName: "dockerfile: build/Dockerfile\ncommand: tool\n",
+			},
+			dockerfile: "build/Dockerfile",
+		},
+		{name: "MissingDirectory", missing: true, hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := useFakeEngine(t)
+			projectDir := filepath.Join(t.TempDir(), "tool")
+			for name, content := range test.files {
+				filePath := filepath.Join(projectDir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+				if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+			}
+			if !test.missing {
+				if err := os.MkdirAll(projectDir, 0755); err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+			}
+
+			project := install.Project{
+				Name:                 "tool",
+				Source:               config.SourceLocal,
+				LocalPath:            projectDir,
+				DestinationDirectory: filepath.Dir(projectDir),
+				DefaultCommand:       "tool",
+				Quiet:                true,
+			}
+			err := project.Install()
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+			if test.hasError {
+				return
+			}
+
+			// The project is referenced in place and marked as local
+			configFile, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			projectConfig := configFile.GetProject("tool")
+			if projectConfig == nil || projectConfig.Source != config.SourceLocal || projectConfig.Path != projectDir {
+				t.Fatalf("Expected a local project at %s, got %+v", projectDir, projectConfig)
+			}
+			if _, err = os.Lstat(filepath.Join(globals.LocalBinDirectory, "tool")); err != nil {
+				t.Errorf("Expected the command to be linked: %v", err)
+			}
+			build := "build -f " + filepath.Join(projectDir, filepath.FromSlash(test.dockerfile))
+
+			// An update rebuilds from the live directory
+			if err = install.ProjectUpdate("tool", "", true); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			content, err := os.ReadFile(calls)
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if count := strings.Count(string(content), build); count != 2 {
+				t.Errorf("Expected 2 builds with %q, got %d in %q", build, count, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package install
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+// useConfigDir points the config file and the lock file at a temporary directory for the duration of the test.
+func useConfigDir(t *testing.T) {
+	t.Helper()
+	configPath := globals.DefaultContainerCliConfigPath
+	t.Cleanup(func() { globals.DefaultContainerCliConfigPath = configPath })
+	globals.DefaultContainerCliConfigPath = filepath.Join(t.TempDir(), "config.yaml")
+}
+
+// createRepository creates a git repository with a single commit of the given files and returns its directory.
+func createRepository(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	git(t, dir, "init", "--quiet")
+	commitFiles(t, dir, files)
+	return dir
+}
+
+// commitFiles writes the given files into the repository and commits them.
+func commitFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+	}
+	git(t, dir, "add", ".")
+	git(t, dir, "-c", "user.name=ccli", "-c", "user.email=ccli@example.com", "commit", "--quiet", "-m", "Update")
+}
+
+// git runs git with the given arguments in dir and returns its trimmed output.
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestInstallLockedMismatch(t *testing.T) {
+	useConfigDir(t)
+	configFile := config.NewContainerCliConfig("docker")
+	if err := configFile.SaveConfig(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	repository := createRepository(t, map[string]string{"Dockerfile": "FROM scratch\n"})
+	lockFile := config.LockFile{
+		Path:     config.DefaultLockFilePath(),
+		Projects: []config.ProjectLock{{Name: "tool", Commit: strings.Repeat("0", 40)}},
+	}
+	if err := lockFile.Save(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	// The working install must survive a refused locked install
+	destination := t.TempDir()
+	installed := filepath.Join(destination, "tool", "installed")
+	if err := os.MkdirAll(filepath.Dir(installed), 0755); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if err := os.WriteFile(installed, nil, 0644); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	project := install.Project{
+		Name:                 "tool",
+		URL:                  "file://" + repository,
+		Source:               config.SourceGit,
+		DestinationDirectory: destination,
+		DefaultCommand:       "tool",
+		Locked:               true,
+	}
+	err := project.Install()
+	if err == nil || !strings.Contains(err.Error(), "does not match the locked commit") {
+		t.Fatalf("Expected the commit to be refused, got %v", err)
+	}
+	if _, err = os.Stat(installed); err != nil {
+		t.Errorf("Expected the installed source to be kept: %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(destination, "tool.locked")); !os.IsNotExist(err) {
+		t.Errorf("Expected the staging directory to be removed, got %v", err)
+	}
+}
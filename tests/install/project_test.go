@@ -1,8 +1,12 @@
 package install
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
 	"gitlab.com/locke-codes/container-cli/internal/install"
 )
 
@@ -43,3 +47,24 @@ func TestCloneProject(t *testing.T) {
 		})
 	}
 }
+
+func TestUnknownEngine(t *testing.T) {
+	useConfigDir(t)
+	configFile := config.ContainerCliConfig{
+		ContainerEngine: "rkt",
+		Path:            globals.DefaultContainerCliConfigPath,
+		Projects:        []config.ProjectConfig{{Name: "tool", Source: config.SourceImage, Image: "tool:1.0"}},
+	}
+	if err := configFile.SaveConfig(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	project := install.Project{Name: "other", Source: config.SourceImage, Image: "other:1.0"}
+	if err := project.Install(); err == nil || !strings.Contains(err.Error(), `invalid container engine "rkt"`) {
+		t.Errorf("Expected the install to fail on the unknown engine, got %v", err)
+	}
+	_, err := install.RunCommand("tool", nil)
+	if err == nil || errors.Is(err, install.ErrUnknownCommand) || !strings.Contains(err.Error(), `"rkt"`) {
+		t.Errorf("Expected the run to fail on the unknown engine, got %v", err)
+	}
+}
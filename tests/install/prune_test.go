@@ -0,0 +1,34 @@
+package install
+
+import (
+	"reflect"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/engine"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestLeftoverContainers(t *testing.T) {
+	containers := []engine.ContainerInfo{
+		{ID: "labelled", Image: "other:latest", State: "exited", Labels: map[string]string{"ccli.project": "tool"}},
+		{ID: "running", Image: "tool:latest", State: "running", Labels: map[string]string{"ccli.project": "tool"}},
+		{ID: "named", Name: "ccli-db-4242", Image: "postgres:16", State: "exited"},
+		{ID: "wrapper", Image: "tool", State: "exited"},
+		{ID: "wrapperTagged", Image: "localhost/tool:latest", State: "exited"},
+		{ID: "otherTag", Image: "tool:0123456789ab", State: "exited"},
+		{ID: "prebuilt", Image: "postgres:16", State: "exited"},
+		{ID: "compose", Name: "app-db-1", Image: "docker.io/library/postgres:16", State: "exited"},
+		{ID: "similar", Image: "tool-extra:latest", State: "exited"},
+		{ID: "registry", Image: "registry.example.com/tool", State: "exited"},
+	}
+
+	leftovers := install.LeftoverContainers(containers, []string{"tool", "db"}, []string{"tool:latest"})
+	var ids []string
+	for _, leftover := range leftovers {
+		ids = append(ids, leftover.ID)
+	}
+	expected := []string{"labelled", "named", "wrapper", "wrapperTagged"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Output mismatch. Expected %+v but got %+v", expected, ids)
+	}
+}
@@ -0,0 +1,88 @@
+package install
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestRollbackTarget(t *testing.T) {
+	type testCase struct {
+		name       string
+		currentTag string
+		commit     string
+		expected   string
+		hasError   bool
+	}
+
+	history := []string{
+		"cccccccccccccccccccccccccccccccccccccccc",
+		"bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		"abaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		"acaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+	}
+
+	tests := []testCase{
+		{name: "PreviousOfLatest", currentTag: "", expected: history[1]},
+		{name: "PreviousOfLatestTag", currentTag: "latest", expected: history[1]},
+		{name: "PreviousOfRolledBack", currentTag: "bbbbbbbbbbbb", expected: history[2]},
+		{name: "NoEarlierCommit", currentTag: "acaaaaaaaaaa", hasError: true},
+		{name: "UnknownCurrentTag", currentTag: "dddddddddddd", hasError: true},
+		{name: "ExplicitPrefix", commit: "bbbb", expected: history[1]},
+		{name: "ExplicitFull", commit: history[3], expected: history[3]},
+		{name: "ExplicitAmbiguous", commit: "a", hasError: true},
+		{name: "ExplicitUnknown", commit: "ffff", hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := install.RollbackTarget(history, test.currentTag, test.commit)
+			if err != nil && !test.hasError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.hasError {
+				t.Fatalf("Expected error but got nil")
+			}
+			if out != test.expected {
+				t.Errorf("Output mismatch. Expected %+v but got %+v", test.expected, out)
+			}
+		})
+	}
+}
+
+func TestHistoryPrebuiltImage(t *testing.T) {
+	calls := useFakeEngine(t)
+	repository := createRepository(t, map[string]string{
+		config.ManifestFileName: "image: registry.example.com/tool:2.0\ncommand: tool\n",
+	})
+	project := install.Project{
+		Name:                 "tool",
+		URL:                  "file://" + repository,
+		Source:               config.SourceGit,
+		DestinationDirectory: t.TempDir(),
+		DefaultCommand:       "tool",
+		Quiet:                true,
+	}
+	if err := project.Install(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	// Push the first commits out of the history
+	for i := 0; i <= globals.ImageHistoryLength; i++ {
+		commitFiles(t, repository, map[string]string{"VERSION": strconv.Itoa(i)})
+		if err := install.ProjectUpdate("tool", "", true); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+	}
+	content, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if strings.Contains(string(content), "image rm") {
+		t.Errorf("Expected the prebuilt image to be kept, got %q", content)
+	}
+}
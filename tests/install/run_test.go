@@ -0,0 +1,97 @@
+package install
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestCommandAlias(t *testing.T) {
+	tests := map[string]string{
+		"ccli":                         "",
+		"/home/user/.local/bin/ccli":   "",
+		"container-cli":                "",
+		"ccli.exe":                     "",
+		"/home/user/.local/bin/tflint": "tflint",
+		"bs":                           "bs",
+	}
+	for arg0, expected := range tests {
+		if out := install.CommandAlias(arg0); out != expected {
+			t.Errorf("CommandAlias(%q): expected %q but got %q", arg0, expected, out)
+		}
+	}
+}
+
+func TestFindCommand(t *testing.T) {
+	configFile := &config.ContainerCliConfig{
+		Projects: []config.ProjectConfig{
+			{Name: "big-salad", Source: config.SourceImage, DefaultCommand: "bs", CommandAlias: "bs"},
+			{
+				Name:           "terraform-tools",
+				Source:         config.SourceImage,
+				DefaultCommand: "terraform",
+				Commands:       []config.CommandConfig{{Alias: "tfsec", Command: "tfsec --no-color"}},
+			},
+		},
+	}
+
+	projectConfig, command := install.FindCommand(configFile, "tfsec")
+	if projectConfig == nil || projectConfig.Name != "terraform-tools" || command.Command != "tfsec --no-color" {
+		t.Errorf("Expected the tfsec command of terraform-tools, got %+v %+v", projectConfig, command)
+	}
+	projectConfig, command = install.FindCommand(configFile, "terraform-tools")
+	if projectConfig == nil || command.Command != "terraform" {
+		t.Errorf("Expected the default command of terraform-tools, got %+v %+v", projectConfig, command)
+	}
+	if projectConfig, _ = install.FindCommand(configFile, "missing"); projectConfig != nil {
+		t.Errorf("Expected no project for an unknown alias, got %+v", projectConfig)
+	}
+}
+
+func TestFindCommandManifest(t *testing.T) {
+	projectDir := t.TempDir()
+	manifest := "commands:\n  - alias: bs-lint\n    command: bs lint\n"
+	if err := os.WriteFile(filepath.Join(projectDir, config.ManifestFileName), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	configFile := &config.ContainerCliConfig{
+		Projects: []config.ProjectConfig{
+			{Name: "big-salad", Source: config.SourceLocal, Path: projectDir, DefaultCommand: "bs", CommandAlias: "bs"},
+		},
+	}
+	projectConfig, command := install.FindCommand(configFile, "bs-lint")
+	if projectConfig == nil || command.Command != "bs lint" {
+		t.Errorf("Expected the bs-lint command of the manifest, got %+v %+v", projectConfig, command)
+	}
+}
+
+func TestRunCommandUnknown(t *testing.T) {
+	for _, withConfig := range []bool{false, true} {
+		useConfigDir(t)
+		if withConfig {
+			configFile := config.NewContainerCliConfig("docker")
+			if err := configFile.SaveConfig(); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+		}
+		// A renamed ccli binary falls back to the command line of ccli
+		if _, err := install.RunCommand("ccli-dev", nil); !errors.Is(err, install.ErrUnknownCommand) {
+			t.Errorf("Expected ErrUnknownCommand with config %t, got %v", withConfig, err)
+		}
+	}
+}
+
+func TestValidateEngine(t *testing.T) {
+	for _, engine := range []string{"", "docker", "podman"} {
+		if err := install.ValidateEngine(engine); err != nil {
+			t.Errorf("Unexpected error for %q: %+v", engine, err)
+		}
+	}
+	if err := install.ValidateEngine("runc"); err == nil {
+		t.Errorf("Expected error for an unsupported engine")
+	}
+}
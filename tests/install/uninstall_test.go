@@ -0,0 +1,85 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+func TestProjectUninstall(t *testing.T) {
+	type testCase struct {
+		name       string
+		local      bool
+		keepImage  bool
+		keepSource bool
+		imageKept  bool
+		sourceKept bool
+	}
+
+	tests := []testCase{
+		{name: "Default"},
+		{name: "KeepImage", keepImage: true, imageKept: true},
+		{name: "KeepSource", keepSource: true, sourceKept: true},
+		{name: "Local", local: true, sourceKept: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := useFakeEngine(t)
+			files := map[string]string{"Dockerfile": "FROM scratch\n"}
+			project := install.Project{
+				Name:           "tool",
+				DefaultCommand: "tool",
+				Quiet:          true,
+			}
+			if test.local {
+				project.Source = config.SourceLocal
+				project.LocalPath = createRepository(t, files)
+				project.DestinationDirectory = filepath.Dir(project.LocalPath)
+			} else {
+				project.Source = config.SourceGit
+				project.URL = "file://" + createRepository(t, files)
+				project.DestinationDirectory = t.TempDir()
+			}
+			if err := project.Install(); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			lockFile, err := config.LoadLockFile(config.DefaultLockFilePath())
+			if err != nil || lockFile.GetProject("tool") == nil {
+				t.Fatalf("Expected the project to be locked: %v", err)
+			}
+
+			if err = install.ProjectUninstall("tool", test.keepImage, test.keepSource); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if _, err = os.Lstat(filepath.Join(globals.LocalBinDirectory, "tool")); !os.IsNotExist(err) {
+				t.Errorf("Expected the command link to be removed, got %v", err)
+			}
+			configFile, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if projectConfig := configFile.GetProject("tool"); projectConfig != nil {
+				t.Errorf("Expected the config entry to be removed, got %+v", projectConfig)
+			}
+			lockFile, err = config.LoadLockFile(config.DefaultLockFilePath())
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if projectLock := lockFile.GetProject("tool"); projectLock != nil {
+				t.Errorf("Expected the lock entry to be removed, got %+v", projectLock)
+			}
+			if removed := countCalls(t, calls, "image rm") > 0; removed == test.imageKept {
+				t.Errorf("Expected the image to be kept: %t, but it was removed: %t", test.imageKept, removed)
+			}
+			if _, err = os.Stat(project.Path()); (err == nil) != test.sourceKept {
+				t.Errorf("Expected the source to be kept: %t, got %v", test.sourceKept, err)
+			}
+		})
+	}
+}
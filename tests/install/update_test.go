@@ -0,0 +1,175 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/locke-codes/container-cli/internal/config"
+	"gitlab.com/locke-codes/container-cli/internal/globals"
+	"gitlab.com/locke-codes/container-cli/internal/install"
+)
+
+// useFakeEngine puts a fake docker client in front of PATH, saves a config file that uses it and points the local bin
+// and log directories at temporary ones. The client reports every image as present, prints its arguments and appends
+// them to the returned file. Every command succeeds, except pulls while CCLI_TEST_PULL_ERROR is set and builds while
+// CCLI_TEST_BUILD_ERROR is set.
+func useFakeEngine(t *testing.T) string {
+	t.Helper()
+	useConfigDir(t)
+	binDir, logDir := globals.LocalBinDirectory, globals.LogDirectory
+	t.Cleanup(func() { globals.LocalBinDirectory, globals.LogDirectory = binDir, logDir })
+	globals.LocalBinDirectory, globals.LogDirectory = t.TempDir(), t.TempDir()
+
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$*\" >> " + calls + "\n" +
+		"if [ \"$1\" = pull ] && [ -n \"$CCLI_TEST_PULL_ERROR\" ]; then echo \"$CCLI_TEST_PULL_ERROR\" >&2; exit 1; fi\n" +
+		"if [ \"$1\" = build ] && [ -n \"$CCLI_TEST_BUILD_ERROR\" ]; then echo \"$CCLI_TEST_BUILD_ERROR\" >&2; exit 1; fi\n" +
+		"if [ \"$1 $2\" = \"image inspect\" ]; then echo '[{\"Id\":\"sha256:0123\",\"RepoDigests\":[]}]'; " +
+		"else echo \"$*\"; fi\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0755); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	configFile := config.NewContainerCliConfig("docker")
+	if err := configFile.SaveConfig(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	return calls
+}
+
+func TestUpdatePinUpToDate(t *testing.T) {
+	useFakeEngine(t)
+	repository := createRepository(t, map[string]string{"Dockerfile": "FROM scratch\n"})
+	git(t, repository, "tag", "v1")
+	project := install.Project{
+		Name:                 "tool",
+		URL:                  "file://" + repository,
+		Source:               config.SourceGit,
+		DestinationDirectory: t.TempDir(),
+		DefaultCommand:       "tool",
+		Quiet:                true,
+	}
+	if err := project.Install(); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	// Pinning to the checked out commit does not rebuild, but the ref must still be recorded
+	if err := install.ProjectUpdate("tool", "v1", true); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	configFile, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if projectConfig := configFile.GetProject("tool"); projectConfig == nil || projectConfig.Ref != "v1" {
+		t.Errorf("Expected the project to be pinned to v1, got %+v", projectConfig)
+	}
+}
+
+// countCalls returns how many commands the fake engine ran with the given first argument.
+func countCalls(t *testing.T, calls, command string) int {
+	t.Helper()
+	content, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	count := 0
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, command+" ") {
+			count++
+		}
+	}
+	return count
+}
+
+func TestUpdateGit(t *testing.T) {
+	type testCase struct {
+		name       string
+		newCommit  bool   // Whether the repository gets a new commit before the update
+		ref        string // Ref given to the update
+		buildError bool
+		builds     int    // Number of builds, including the one of the install
+		updated    bool   // Whether the update is installed
+		branch     string // Branch the checkout is on after the update
+	}
+
+	tests := []testCase{
+		{name: "Unchanged", builds: 1, branch: "main"},
+		{name: "Changed", newCommit: true, builds: 2, updated: true, branch: "main"},
+		{name: "BuildFails", newCommit: true, buildError: true, builds: 2, branch: "main"},
+		{name: "BuildFailsNewRef", ref: "dev", buildError: true, builds: 2, branch: "main"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := useFakeEngine(t)
+			repository := createRepository(t, map[string]string{"Dockerfile": "FROM scratch\n"})
+			git(t, repository, "branch", "-M", "main")
+			git(t, repository, "branch", "dev")
+			project := install.Project{
+				Name:                 "tool",
+				URL:                  "file://" + repository,
+				Source:               config.SourceGit,
+				DestinationDirectory: t.TempDir(),
+				DefaultCommand:       "tool",
+				Quiet:                true,
+			}
+			if err := project.Install(); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			installed := git(t, project.Path(), "rev-parse", "HEAD")
+
+			if test.newCommit {
+				commitFiles(t, repository, map[string]string{"VERSION": "2"})
+			}
+			if test.ref != "" {
+				git(t, repository, "checkout", "--quiet", test.ref)
+				commitFiles(t, repository, map[string]string{"VERSION": "dev"})
+				git(t, repository, "checkout", "--quiet", "main")
+			}
+			latest := git(t, repository, "rev-parse", "HEAD")
+			if test.ref != "" {
+				latest = git(t, repository, "rev-parse", test.ref)
+			}
+			if test.buildError {
+				t.Setenv("CCLI_TEST_BUILD_ERROR", "build failed")
+			}
+			err := install.ProjectUpdate("tool", test.ref, true)
+			if err != nil && !test.buildError {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if err == nil && test.buildError {
+				t.Fatalf("Expected error but got nil")
+			}
+
+			if builds := countCalls(t, calls, "build"); builds != test.builds {
+				t.Errorf("Expected %d builds, got %d", test.builds, builds)
+			}
+			expected := installed
+			if test.updated {
+				expected = latest
+			}
+			if commit := git(t, project.Path(), "rev-parse", "HEAD"); commit != expected {
+				t.Errorf("Expected the checkout at %s, got %s", expected, commit)
+			}
+			if branch := git(t, project.Path(), "symbolic-ref", "--short", "HEAD"); branch != test.branch {
+				t.Errorf("Expected the checkout on %s, got %s", test.branch, branch)
+			}
+			configFile, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			projectConfig := configFile.GetProject("tool")
+			if projectConfig == nil || projectConfig.Commit != expected || projectConfig.Ref != "" {
+				t.Errorf("Expected the project config at %s, got %+v", expected, projectConfig)
+			}
+			if _, err = os.Lstat(filepath.Join(globals.LocalBinDirectory, "tool")); err != nil {
+				t.Errorf("Expected the command link to be kept: %v", err)
+			}
+		})
+	}
+}